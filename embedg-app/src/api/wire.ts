@@ -64,10 +64,52 @@ export type CustomBotGetResponseWire = APIResponse<CustomBotInfoWire>;
 export interface CustomBotDisableResponseDataWire {
 }
 export type CustomBotDisableResponseWire = APIResponse<CustomBotDisableResponseDataWire>;
+export interface CustomBotStatusWire {
+  gateway: CustomBotGatewayStatusWire;
+  interactions: CustomBotInteractionsStatusWire;
+  commands: CustomBotCommandsStatusWire;
+}
+export interface CustomBotGatewayStatusWire {
+  connected: boolean;
+  token_valid: boolean;
+  ready_at: null | string /* RFC3339 */;
+  disconnected_at: null | string /* RFC3339 */;
+  disconnect_reason: null | string;
+  latency_ms: null | number;
+  reconnects: number /* int */;
+  /**
+   * Whether the session receives member events, null if the custom bot hasn't connected yet.
+   */
+  members_intent: null | boolean;
+}
+export interface CustomBotInteractionsStatusWire {
+  endpoint_url: string;
+  last_interaction_at: null | string /* RFC3339 */;
+  last_signature_failure_at: null | string /* RFC3339 */;
+}
+export interface CustomBotCommandsStatusWire {
+  in_sync: boolean;
+  missing: string[];
+  outdated: string[];
+  unknown: string[];
+  error: null | string;
+}
+export type CustomBotStatusResponseWire = APIResponse<CustomBotStatusWire>;
+/**
+ * Custom commands have the same types as Discord's application commands.
+ */
+export const CustomCommandTypeChatInput = 1;
+export const CustomCommandTypeUser = 2;
+export const CustomCommandTypeMessage = 3;
 export interface CustomCommandWire {
   id: string;
+  type: number /* int */;
   name: string;
   description: string;
+  name_localizations: { [key: string]: string};
+  description_localizations: { [key: string]: string};
+  default_member_permissions: null | string;
+  contexts: number /* int */[];
   enabled: boolean;
   parameters: CustomCommandParameterWire[];
   actions: Record<string, any> | null;
@@ -79,20 +121,64 @@ export interface CustomCommandParameterWire {
   id: number /* int */;
   name: string;
   description: string;
+  name_localizations?: { [key: string]: string};
+  description_localizations?: { [key: string]: string};
   type: number /* int */;
+  required?: null | boolean;
+  choices?: CustomCommandParameterChoiceWire[];
+  min_value?: null | number;
+  max_value?: null | number;
+  min_length?: null | number;
+  max_length?: null | number;
+  channel_types?: number /* int */[];
+  autocomplete?: CustomCommandAutocompleteWire;
+}
+/**
+ * Sources of autocomplete suggestions for custom command parameters.
+ */
+export const CustomCommandAutocompleteStatic = "static";
+export const CustomCommandAutocompleteKV = "kv";
+export const CustomCommandAutocompleteTemplate = "template";
+/**
+ * CustomCommandAutocompleteWire configures where the suggestions for a parameter come from.
+ * The template outputs one suggestion per line or a JSON array of suggestions.
+ */
+export interface CustomCommandAutocompleteWire {
+  type: string;
+  values?: string[];
+  key_prefix?: string;
+  template?: string;
+}
+export interface CustomCommandParameterChoiceWire {
+  name: string;
+  name_localizations?: { [key: string]: string};
+  /**
+   * Value is a string for string parameters and a number for integer and number parameters.
+   */
+  value: any;
 }
 export type CustomCommandsListResponseWire = APIResponse<CustomCommandWire[]>;
 export type CustomCommandGetResponseWire = APIResponse<CustomCommandWire>;
 export interface CustomCommandCreateRequestWire {
+  type?: number /* int */;
   name: string;
   description: string;
+  name_localizations?: { [key: string]: string};
+  description_localizations?: { [key: string]: string};
+  default_member_permissions?: null | string;
+  contexts?: number /* int */[];
   parameters: CustomCommandParameterWire[];
   actions: Record<string, any> | null;
 }
 export type CustomCommandCreateResponseWire = APIResponse<CustomCommandWire>;
 export interface CustomCommandUpdateRequestWire {
+  type?: number /* int */;
   name: string;
   description: string;
+  name_localizations?: { [key: string]: string};
+  description_localizations?: { [key: string]: string};
+  default_member_permissions?: null | string;
+  contexts?: number /* int */[];
   enabled: boolean;
   parameters: CustomCommandParameterWire[];
   actions: Record<string, any> | null;
@@ -100,8 +186,97 @@ export interface CustomCommandUpdateRequestWire {
 export type CustomCommandUpdateResponseWire = APIResponse<CustomCommandWire>;
 export type CustomCommandDeleteResponseWire = APIResponse<{
   }>;
-export type CustomCommandsDeployResponseWire = APIResponse<{
-  }>;
+/**
+ * Actions that a deployment takes for a single command.
+ */
+export const CustomCommandChangeCreate = "create";
+export const CustomCommandChangeUpdate = "update";
+export const CustomCommandChangeDelete = "delete";
+export interface CustomCommandChangeWire {
+  action: string;
+  type: number /* int */;
+  name: string;
+}
+export interface CustomCommandsDeployPlanWire {
+  in_sync: boolean;
+  changes: CustomCommandChangeWire[];
+}
+export type CustomCommandsDeployPlanResponseWire = APIResponse<CustomCommandsDeployPlanWire>;
+export interface CustomCommandsDeployResultWire {
+  changes: CustomCommandChangeWire[];
+  /**
+   * DeploymentID is null when there was nothing to deploy.
+   */
+  deployment_id: null | string;
+}
+export type CustomCommandsDeployResponseWire = APIResponse<CustomCommandsDeployResultWire>;
+/**
+ * CustomCommandDeployedWire is a command as it was registered with Discord by a deployment.
+ */
+export interface CustomCommandDeployedWire {
+  id: string;
+  type: number /* int */;
+  name: string;
+}
+export interface CustomCommandDeploymentWire {
+  id: string;
+  application_id: string;
+  user_id: string;
+  commands: CustomCommandDeployedWire[];
+  changes: CustomCommandChangeWire[];
+  rollback_of: null | string;
+  /**
+   * Partial is set when deploying failed midway and only some of the changes have been made.
+   */
+  partial: boolean;
+  created_at: string /* RFC3339 */;
+}
+export type CustomCommandDeploymentsListResponseWire = APIResponse<CustomCommandDeploymentWire[]>;
+export type CustomCommandDeploymentRollbackResponseWire = APIResponse<CustomCommandsDeployResultWire>;
+/**
+ * CustomCommandBundleVersion is the version of the bundle format, it must be increased on breaking changes.
+ */
+export const CustomCommandBundleVersion = 1;
+/**
+ * CustomCommandBundleWire contains all custom commands of a guild so they can be imported into another guild.
+ */
+export interface CustomCommandBundleWire {
+  version: number /* int */;
+  exported_at: string /* RFC3339 */;
+  commands: CustomCommandBundleEntryWire[];
+}
+/**
+ * CustomCommandBundleEntryWire is a single command in a bundle, it has the same fields as an update request.
+ */
+export type CustomCommandBundleEntryWire = CustomCommandUpdateRequestWire;
+export type CustomCommandsExportResponseWire = APIResponse<CustomCommandBundleWire>;
+/**
+ * How commands in a bundle are handled that have the same name and type as an existing command.
+ */
+export const CustomCommandImportConflictError = "error";
+export const CustomCommandImportConflictSkip = "skip";
+export const CustomCommandImportConflictReplace = "replace";
+export interface CustomCommandsImportRequestWire {
+  bundle: CustomCommandBundleWire;
+  on_conflict?: string;
+}
+export interface CustomCommandsImportResponseDataWire {
+  created: CustomCommandWire[];
+  updated: CustomCommandWire[];
+  skipped: string[];
+  missing_references: CustomCommandImportMissingReferenceWire[];
+}
+export const CustomCommandImportReferenceRole = "role";
+export const CustomCommandImportReferenceSavedMessage = "saved_message";
+/**
+ * CustomCommandImportMissingReferenceWire is a role or saved message that the actions of an imported command refer to but that doesn't exist on the server.
+ */
+export interface CustomCommandImportMissingReferenceWire {
+  command: string;
+  type: string;
+  target_id: string;
+}
+export type CustomCommandsImportResponseWire = APIResponse<CustomCommandsImportResponseDataWire>;
 
 //////////
 // source: embeds_links.go
@@ -134,6 +309,45 @@ export interface EmbedLinkOEmbedResponseWire {
   provider_url?: string;
 }
 
+//////////
+// source: event_trigger.go
+
+export interface EventTriggerWire {
+  id: string;
+  creator_id: string;
+  guild_id: string;
+  event: string;
+  name: string;
+  saved_message_id: null | string;
+  channel_id: null | string;
+  actions: Record<string, any> | null;
+  enabled: boolean;
+  created_at: string /* RFC3339 */;
+  updated_at: string /* RFC3339 */;
+}
+export type EventTriggerListResponseWire = APIResponse<EventTriggerWire[]>;
+export type EventTriggerGetResponseWire = APIResponse<EventTriggerWire>;
+export interface EventTriggerCreateRequestWire {
+  event: string;
+  name: string;
+  saved_message_id: null | string;
+  channel_id: null | string;
+  actions: Record<string, any> | null;
+  enabled: boolean;
+}
+export type EventTriggerCreateResponseWire = APIResponse<EventTriggerWire>;
+export interface EventTriggerUpdateRequestWire {
+  event: string;
+  name: string;
+  saved_message_id: null | string;
+  channel_id: null | string;
+  actions: Record<string, any> | null;
+  enabled: boolean;
+}
+export type EventTriggerUpdateResponseWire = APIResponse<EventTriggerWire>;
+export type EventTriggerDeleteResponseWire = APIResponse<{
+  }>;
+
 //////////
 // source: guild.go
 
@@ -202,6 +416,48 @@ export interface ImageWire {
 export type UploadImageResponseWire = APIResponse<ImageWire>;
 export type GetImageResponseWire = APIResponse<ImageWire>;
 
+//////////
+// source: kv_entries.go
+
+export interface KVEntryWire {
+  key: string;
+  value: string;
+  expires_at: null | string /* RFC3339 */;
+  created_at: string /* RFC3339 */;
+  updated_at: string /* RFC3339 */;
+}
+export interface KVEntryListResponseDataWire {
+  entries: KVEntryWire[];
+  total: number /* int */;
+}
+export type KVEntryListResponseWire = APIResponse<KVEntryListResponseDataWire>;
+export type KVEntryGetResponseWire = APIResponse<KVEntryWire>;
+export interface KVEntryUpdateRequestWire {
+  value: string;
+  expires_at: null | string /* RFC3339 */;
+}
+export type KVEntryUpdateResponseWire = APIResponse<KVEntryWire>;
+export type KVEntryDeleteResponseWire = APIResponse<{
+  }>;
+export interface KVEntryImportWire {
+  key: string;
+  value: string;
+  expires_at: null | string /* RFC3339 */;
+}
+export interface KVEntriesImportRequestWire {
+  entries: KVEntryImportWire[];
+}
+export interface KVEntriesImportResponseDataWire {
+  created_count: number /* int */;
+  updated_count: number /* int */;
+}
+export type KVEntriesImportResponseWire = APIResponse<KVEntriesImportResponseDataWire>;
+export interface KVEntriesExportResponseDataWire {
+  entries: KVEntryImportWire[];
+  total: number /* int */;
+}
+export type KVEntriesExportResponseWire = APIResponse<KVEntriesExportResponseDataWire>;
+
 //////////
 // source: message.go
 
@@ -239,6 +495,18 @@ export interface SavedMessageImportDataWire {
   description: null | string;
   data: Record<string, any> | null;
 }
+export interface SavedMessageVariantWire {
+  locale: string;
+  data: Record<string, any> | null;
+  updated_at: string /* RFC3339 */;
+}
+export type SavedMessageVariantListResponseWire = APIResponse<SavedMessageVariantWire[]>;
+export interface SavedMessageVariantUpdateRequestWire {
+  data: Record<string, any> | null;
+}
+export type SavedMessageVariantUpdateResponseWire = APIResponse<SavedMessageVariantWire>;
+export type SavedMessageVariantDeleteResponseWire = APIResponse<{
+  }>;
 export interface MessageSendToWebhookRequestWire {
   webhook_type: string;
   webhook_id: string;
@@ -299,9 +567,11 @@ export interface GetPremiumPlanFeaturesResponseDataWire {
   is_premium: boolean;
   max_image_upload_size: number /* int */;
   max_scheduled_messages: number /* int */;
+  max_event_triggers: number /* int */;
   periodic_scheduled_messages: boolean;
   max_template_ops: number /* int */;
   max_kv_keys: number /* int */;
+  http_requests: boolean;
 }
 export type GetPremiumPlanFeaturesResponseWire = APIResponse<GetPremiumPlanFeaturesResponseDataWire>;
 export interface PremiumEntitlementWire {
@@ -401,6 +671,108 @@ export interface SharedMessageCreateRequestWire {
 export type SharedMessageCreateResponseWire = APIResponse<SharedMessageWire>;
 export type SharedMessageGetResponseWire = APIResponse<SharedMessageWire>;
 
+//////////
+// source: template.go
+
+export interface TemplateLintRequestWire {
+  data: Record<string, any> | null;
+}
+export interface TemplateDiagnosticWire {
+  path: string;
+  line: number /* int */;
+  column: number /* int */;
+  message: string;
+  suggestions: string[];
+}
+export interface TemplateLintResponseDataWire {
+  valid: boolean;
+  diagnostics: TemplateDiagnosticWire[];
+}
+export type TemplateLintResponseWire = APIResponse<TemplateLintResponseDataWire>;
+export interface TemplatePreviewUserWire {
+  id: string;
+  username: string;
+  global_name: null | string;
+  avatar: null | string;
+  nick: null | string;
+  roles: string[];
+}
+export interface TemplatePreviewCommandWire {
+  name: string;
+  options: { [key: string]: any};
+}
+export interface TemplatePreviewRequestWire {
+  data: Record<string, any> | null;
+  channel_id: null | string;
+  user?: TemplatePreviewUserWire;
+  command?: TemplatePreviewCommandWire;
+  kv: { [key: string]: string};
+  locale: null | string;
+}
+export interface TemplatePreviewResponseDataWire {
+  data: Record<string, any> | null;
+  error: null | string;
+}
+export type TemplatePreviewResponseWire = APIResponse<TemplatePreviewResponseDataWire>;
+export interface TemplateSnippetWire {
+  name: string;
+  content: string;
+  created_at: string /* RFC3339 */;
+  updated_at: string /* RFC3339 */;
+}
+export type TemplateSnippetListResponseWire = APIResponse<TemplateSnippetWire[]>;
+export interface TemplateSnippetUpdateRequestWire {
+  content: string;
+}
+export type TemplateSnippetUpdateResponseWire = APIResponse<TemplateSnippetWire>;
+export type TemplateSnippetDeleteResponseWire = APIResponse<{
+  }>;
+export interface TemplateHTTPHostWire {
+  host: string;
+  created_at: string /* RFC3339 */;
+}
+export type TemplateHTTPHostListResponseWire = APIResponse<TemplateHTTPHostWire[]>;
+export interface TemplateHTTPHostCreateRequestWire {
+  host: string;
+}
+export type TemplateHTTPHostCreateResponseWire = APIResponse<TemplateHTTPHostWire>;
+export type TemplateHTTPHostDeleteResponseWire = APIResponse<{
+  }>;
+export interface TemplateVariablesConvertRequestWire {
+  apply: boolean;
+}
+export interface TemplateVariablesChangeWire {
+  type: string;
+  id: string;
+  name: string;
+  converted: number /* int */;
+  unsupported: string[];
+  data: Record<string, any> | null;
+}
+export interface TemplateVariablesConvertResponseDataWire {
+  applied: boolean;
+  converted: number /* int */;
+  changes: TemplateVariablesChangeWire[];
+}
+export type TemplateVariablesConvertResponseWire = APIResponse<TemplateVariablesConvertResponseDataWire>;
+
+//////////
+// source: translation.go
+
+export interface TranslationWire {
+  locale: string;
+  key: string;
+  value: string;
+  updated_at: string /* RFC3339 */;
+}
+export type TranslationListResponseWire = APIResponse<TranslationWire[]>;
+export interface TranslationsUpdateRequestWire {
+  translations: { [key: string]: string};
+}
+export type TranslationsUpdateResponseWire = APIResponse<TranslationWire[]>;
+export type TranslationsDeleteResponseWire = APIResponse<{
+  }>;
+
 //////////
 // source: user.go
 
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/merlinfuchs/discordgo"
+	"github.com/merlinfuchs/embed-generator/embedg-server/actions/template"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/merlinfuchs/embed-generator/embedg-server/kv_entries"
+	"github.com/rs/zerolog/log"
+)
+
+// autocompleteTimeout leaves enough time to respond before Discord gives up on the interaction.
+const autocompleteTimeout = 2 * time.Second
+
+const maxAutocompleteChoices = 25
+const maxAutocompleteChoiceLength = 100
+
+// commandParameter is the part of a custom command parameter that is needed for autocomplete.
+type commandParameter struct {
+	Name         string               `json:"name"`
+	Type         int                  `json:"type"`
+	Autocomplete *commandAutocomplete `json:"autocomplete"`
+}
+
+type commandAutocomplete struct {
+	Type      string   `json:"type"`
+	Values    []string `json:"values"`
+	KeyPrefix string   `json:"key_prefix"`
+	Template  string   `json:"template"`
+}
+
+// HandleAutocompleteInteraction responds with the suggestions for the focused option of a custom command.
+// It always responds, without suggestions if something went wrong, so the user isn't left with a failed interaction.
+func (m *ActionHandler) HandleAutocompleteInteraction(s *discordgo.Session, i Interaction) error {
+	ctx, cancel := context.WithTimeout(context.Background(), autocompleteTimeout)
+	defer cancel()
+
+	choices, err := m.autocompleteChoices(ctx, s, i.Interaction())
+
+	i.Respond(&discordgo.InteractionResponseData{
+		Choices: choices,
+	}, discordgo.InteractionApplicationCommandAutocompleteResult)
+
+	return err
+}
+
+func (m *ActionHandler) autocompleteChoices(ctx context.Context, s *discordgo.Session, interaction *discordgo.Interaction) ([]*discordgo.ApplicationCommandOptionChoice, error) {
+	data := interaction.ApplicationCommandData()
+	focused := focusedOption(data.Options)
+	if focused == nil {
+		return nil, nil
+	}
+
+	command, err := m.getCustomCommand(ctx, interaction)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get custom command: %w", err)
+	}
+
+	var params []commandParameter
+	if err := json.Unmarshal(command.Parameters, &params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal command parameters: %w", err)
+	}
+
+	var param *commandParameter
+	for i := range params {
+		if params[i].Name == focused.Name {
+			param = &params[i]
+			break
+		}
+	}
+	if param == nil || param.Autocomplete == nil {
+		return nil, nil
+	}
+
+	// The focused value is whatever the user has typed so far, so it's always treated as text
+	input := ""
+	if focused.Value != nil {
+		input = fmt.Sprint(focused.Value)
+	}
+
+	var suggestions []string
+	switch param.Autocomplete.Type {
+	case "static":
+		suggestions = param.Autocomplete.Values
+	case "kv":
+		suggestions, err = m.kvSuggestions(ctx, interaction.GuildID, param.Autocomplete.KeyPrefix, input)
+	case "template":
+		suggestions, err = m.templateSuggestions(ctx, s, interaction, param.Autocomplete.Template)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestionsToChoices(suggestions, input, discordgo.ApplicationCommandOptionType(param.Type)), nil
+}
+
+// kvSuggestions returns the keys that start with the prefix followed by the input, without the prefix.
+func (m *ActionHandler) kvSuggestions(ctx context.Context, guildID string, prefix string, input string) ([]string, error) {
+	keys, err := m.pg.Q.SearchKVEntryKeys(ctx, pgmodel.SearchKVEntryKeysParams{
+		Key:     escapeLikePattern(prefix+input) + "%",
+		GuildID: guildID,
+		MaxKeys: maxAutocompleteChoices,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search KV entries: %w", err)
+	}
+
+	res := make([]string, len(keys))
+	for i, key := range keys {
+		res[i] = strings.TrimPrefix(key, prefix)
+	}
+	return res, nil
+}
+
+// templateSuggestions executes the template which outputs either a JSON array or one suggestion per line.
+// The template runs on every keystroke of the user, so changes to KV entries are discarded and HTTP requests aren't available.
+func (m *ActionHandler) templateSuggestions(ctx context.Context, s *discordgo.Session, interaction *discordgo.Interaction, text string) ([]string, error) {
+	features, err := m.planStore.GetPlanFeaturesForGuild(ctx, interaction.GuildID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get plan features: %w", err)
+	}
+
+	templates := template.NewContext(
+		ctx, "AUTOCOMPLETE", features.MaxTemplateOps,
+		template.NewInteractionProvider(s.State, interaction),
+		template.NewKVProvider(interaction.GuildID, kv_entries.NewOverlayStore(m.pg, interaction.GuildID, nil), features.MaxKVKeys),
+		template.NewEntityProvider(m.state, m.rest, interaction.GuildID),
+		template.NewTranslationProvider(interaction.GuildID, m.pg, interactionLocales(interaction)...),
+		template.NewSnippetProvider(interaction.GuildID, m.pg),
+	)
+
+	output, err := templates.ParseAndExecute(text)
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to execute autocomplete template")
+		return nil, nil
+	}
+
+	output = strings.TrimSpace(output)
+
+	var values []interface{}
+	if strings.HasPrefix(output, "[") && json.Unmarshal([]byte(output), &values) == nil {
+		res := make([]string, 0, len(values))
+		for _, v := range values {
+			if v != nil {
+				res = append(res, fmt.Sprint(v))
+			}
+		}
+		return res, nil
+	}
+
+	return strings.Split(output, "\n"), nil
+}
+
+// suggestionsToChoices filters the suggestions by the input and converts them to the type of the option.
+func suggestionsToChoices(suggestions []string, input string, optionType discordgo.ApplicationCommandOptionType) []*discordgo.ApplicationCommandOptionChoice {
+	input = strings.ToLower(input)
+
+	res := make([]*discordgo.ApplicationCommandOptionChoice, 0, min(len(suggestions), maxAutocompleteChoices))
+	for _, suggestion := range suggestions {
+		if len(res) >= maxAutocompleteChoices {
+			break
+		}
+
+		suggestion = strings.TrimSpace(suggestion)
+		if suggestion == "" || len(suggestion) > maxAutocompleteChoiceLength {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(suggestion), input) {
+			continue
+		}
+
+		var value interface{} = suggestion
+		if optionType == discordgo.ApplicationCommandOptionInteger {
+			v, err := strconv.ParseInt(suggestion, 10, 64)
+			if err != nil {
+				continue
+			}
+			value = v
+		}
+
+		res = append(res, &discordgo.ApplicationCommandOptionChoice{
+			Name:  suggestion,
+			Value: value,
+		})
+	}
+
+	return res
+}
+
+func focusedOption(options []*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	for _, opt := range options {
+		if opt.Focused {
+			return opt
+		}
+		if focused := focusedOption(opt.Options); focused != nil {
+			return focused
+		}
+	}
+	return nil
+}
+
+var likePatternReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLikePattern(s string) string {
+	return likePatternReplacer.Replace(s)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
 	"strconv"
@@ -14,8 +15,10 @@ import (
 	"github.com/merlinfuchs/embed-generator/embedg-server/actions/parser"
 	"github.com/merlinfuchs/embed-generator/embedg-server/actions/template"
 	"github.com/merlinfuchs/embed-generator/embedg-server/actions/variables"
+	"github.com/merlinfuchs/embed-generator/embedg-server/bot/rest"
 	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres"
 	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/merlinfuchs/embed-generator/embedg-server/model"
 	"github.com/merlinfuchs/embed-generator/embedg-server/store"
 	"github.com/rs/zerolog/log"
 	"github.com/sqlc-dev/pqtype"
@@ -28,13 +31,17 @@ type ActionHandler struct {
 	pg        *postgres.PostgresStore
 	parser    *parser.ActionParser
 	planStore store.PlanStore
+	state     *discordgo.State
+	rest      rest.RestClient
 }
 
-func New(pg *postgres.PostgresStore, parser *parser.ActionParser, planStore store.PlanStore) *ActionHandler {
+func New(pg *postgres.PostgresStore, parser *parser.ActionParser, planStore store.PlanStore, state *discordgo.State, rest rest.RestClient) *ActionHandler {
 	return &ActionHandler{
 		pg:        pg,
 		parser:    parser,
 		planStore: planStore,
+		state:     state,
+		rest:      rest,
 	}
 }
 
@@ -71,22 +78,14 @@ func (m *ActionHandler) HandleActionInteraction(s *discordgo.Session, i Interact
 		rawActions = col.Actions
 		rawDerivedPerms = col.DerivedPermissions
 	} else if interaction.Type == discordgo.InteractionApplicationCommand {
-		data := interaction.ApplicationCommandData()
-		fullName := data.Name
-		for _, opt := range data.Options {
-			if opt.Type == discordgo.ApplicationCommandOptionSubCommand {
-				fullName += " " + opt.Name
-			} else if opt.Type == discordgo.ApplicationCommandOptionSubCommandGroup {
-				fullName += " " + opt.Name + " " + opt.Options[0].Name
-			}
-		}
-
-		col, err := m.pg.Q.GetCustomCommandByName(context.TODO(), pgmodel.GetCustomCommandByNameParams{
-			Name:    fullName,
-			GuildID: interaction.GuildID,
-		})
+		col, err := m.getCustomCommand(context.TODO(), interaction)
 		if err != nil {
 			if err == sql.ErrNoRows {
+				// The registered commands can differ from the custom commands after rolling back a deployment
+				i.Respond(&discordgo.InteractionResponseData{
+					Content: "This command doesn't exist anymore, the commands of this server have to be deployed again.",
+					Flags:   discordgo.MessageFlagsEphemeral,
+				})
 				return nil
 			}
 
@@ -130,11 +129,10 @@ func (m *ActionHandler) HandleActionInteraction(s *discordgo.Session, i Interact
 		return fmt.Errorf("could not get plan features: %w", err)
 	}
 
-	templates := template.NewContext(
-		"HANDLE_ACTION", features.MaxTemplateOps,
-		template.NewInteractionProvider(s.State, interaction),
-		template.NewKVProvider(interaction.GuildID, m.pg, features.MaxKVKeys),
-	)
+	templateCtx, cancel := context.WithTimeout(context.Background(), template.DefaultTimeout)
+	defer cancel()
+
+	templates := m.templateContext(templateCtx, s, interaction, features)
 
 	for _, action := range actionSet.Actions {
 		switch action.Type {
@@ -269,8 +267,17 @@ func (m *ActionHandler) HandleActionInteraction(s *discordgo.Session, i Interact
 				return err
 			}
 
+			// Ephemeral responses are only seen by the user so we can use the variant for their locale
+			rawData := msg.Data
+			if !action.Public {
+				rawData, err = m.savedMessageData(context.TODO(), msg, interaction.Locale)
+				if err != nil {
+					return err
+				}
+			}
+
 			data := &actions.MessageWithActions{}
-			err = json.Unmarshal(msg.Data, data)
+			err = json.Unmarshal(rawData, data)
 			if err != nil {
 				return err
 			}
@@ -320,7 +327,7 @@ func (m *ActionHandler) HandleActionInteraction(s *discordgo.Session, i Interact
 				},
 			})
 			if newMsg != nil && !legacyPermissions {
-				err = m.parser.CreateActionsForMessage(context.TODO(), data.Actions, derivedPerms, newMsg.ID, !action.Public)
+				err = m.parser.CreateActionsForMessage(context.TODO(), data.Actions, derivedPerms, interaction.GuildID, newMsg.ID, !action.Public)
 				if err != nil {
 					log.Error().Err(err).Msg("failed to create actions for message")
 					return err
@@ -363,8 +370,13 @@ func (m *ActionHandler) HandleActionInteraction(s *discordgo.Session, i Interact
 				return err
 			}
 
+			rawData, err := m.savedMessageData(context.TODO(), msg, interaction.Locale)
+			if err != nil {
+				return err
+			}
+
 			data := &actions.MessageWithActions{}
-			err = json.Unmarshal(msg.Data, data)
+			err = json.Unmarshal(rawData, data)
 			if err != nil {
 				return err
 			}
@@ -450,7 +462,7 @@ func (m *ActionHandler) HandleActionInteraction(s *discordgo.Session, i Interact
 
 			if !legacyPermissions {
 				ephemeral := interaction.Message.Flags&discordgo.MessageFlagsEphemeral != 0
-				err = m.parser.CreateActionsForMessage(context.TODO(), data.Actions, derivedPerms, interaction.Message.ID, ephemeral)
+				err = m.parser.CreateActionsForMessage(context.TODO(), data.Actions, derivedPerms, interaction.GuildID, interaction.Message.ID, ephemeral)
 				if err != nil {
 					log.Error().Err(err).Msg("failed to create actions for message")
 					return err
@@ -508,11 +520,7 @@ func (m *ActionHandler) HandleActionInteraction(s *discordgo.Session, i Interact
 func executeTemplate(i Interaction, templates *template.TemplateContext, text string) (string, bool) {
 	res, err := templates.ParseAndExecute(text)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to execute template")
-		i.Respond(&discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("Failed to execute template variables:\n```%s```", err.Error()),
-			Flags:   discordgo.MessageFlagsEphemeral,
-		})
+		respondTemplateError(i, err)
 		return "", false
 	}
 	return res, true
@@ -520,13 +528,91 @@ func executeTemplate(i Interaction, templates *template.TemplateContext, text st
 
 func executeTemplateMessage(i Interaction, templates *template.TemplateContext, m *actions.MessageWithActions) bool {
 	if err := templates.ParseAndExecuteMessage(m); err != nil {
-		log.Error().Err(err).Msg("Failed to execute template")
+		respondTemplateError(i, err)
+		return false
+	}
+
+	return true
+}
+
+func respondTemplateError(i Interaction, err error) {
+	if errors.Is(err, template.ErrExecutionTimeout) {
+		log.Warn().Err(err).Msg("Template execution timed out")
 		i.Respond(&discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("Failed to execute template variables:\n```%s```", err.Error()),
+			Content: "The template variables took too long to execute and were aborted.",
 			Flags:   discordgo.MessageFlagsEphemeral,
 		})
-		return false
+		return
 	}
 
-	return true
+	log.Error().Err(err).Msg("Failed to execute template")
+	i.Respond(&discordgo.InteractionResponseData{
+		Content: fmt.Sprintf("Failed to execute template variables:\n```%s```", err.Error()),
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+}
+
+// getCustomCommand returns the custom command that was used, including its subcommand and subcommand group.
+func (m *ActionHandler) getCustomCommand(ctx context.Context, interaction *discordgo.Interaction) (pgmodel.CustomCommand, error) {
+	data := interaction.ApplicationCommandData()
+	fullName := data.Name
+	for _, opt := range data.Options {
+		if opt.Type == discordgo.ApplicationCommandOptionSubCommand {
+			fullName += " " + opt.Name
+		} else if opt.Type == discordgo.ApplicationCommandOptionSubCommandGroup {
+			fullName += " " + opt.Name + " " + opt.Options[0].Name
+		}
+	}
+
+	// Context menu commands can share their name with a chat command
+	commandType := data.CommandType
+	if commandType == 0 {
+		commandType = discordgo.ChatApplicationCommand
+	}
+
+	return m.pg.Q.GetCustomCommandByName(ctx, pgmodel.GetCustomCommandByNameParams{
+		Name:    fullName,
+		GuildID: interaction.GuildID,
+		Type:    int16(commandType),
+	})
+}
+
+// templateContext creates the context for templates that are executed in response to the interaction.
+func (m *ActionHandler) templateContext(ctx context.Context, s *discordgo.Session, interaction *discordgo.Interaction, features model.PlanFeatures) *template.TemplateContext {
+	return template.NewContext(
+		ctx, "HANDLE_ACTION", features.MaxTemplateOps,
+		template.NewInteractionProvider(s.State, interaction),
+		template.NewKVProvider(interaction.GuildID, m.pg, features.MaxKVKeys),
+		template.NewEntityProvider(m.state, m.rest, interaction.GuildID),
+		template.NewTranslationProvider(interaction.GuildID, m.pg, interactionLocales(interaction)...),
+		template.NewSnippetProvider(interaction.GuildID, m.pg),
+		template.NewHTTPProvider(interaction.GuildID, m.pg, nil, features.HTTPRequests),
+	)
+}
+
+// interactionLocales returns the locales of the user and the guild in order of preference.
+func interactionLocales(interaction *discordgo.Interaction) []string {
+	locales := []string{string(interaction.Locale)}
+	if interaction.GuildLocale != nil {
+		locales = append(locales, string(*interaction.GuildLocale))
+	}
+	return locales
+}
+
+// savedMessageData returns the data of the variant that best matches the locale or the default data of the saved message.
+func (m *ActionHandler) savedMessageData(ctx context.Context, msg pgmodel.SavedMessage, locale discordgo.Locale) (json.RawMessage, error) {
+	for _, l := range template.LocaleFallbacks(string(locale)) {
+		variant, err := m.pg.Q.GetSavedMessageVariant(ctx, pgmodel.GetSavedMessageVariantParams{
+			SavedMessageID: msg.ID,
+			Locale:         l,
+		})
+		if err == nil {
+			return variant.Data, nil
+		}
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to get saved message variant: %w", err)
+		}
+	}
+
+	return msg.Data, nil
 }
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 
@@ -12,7 +13,7 @@ import (
 	"github.com/sqlc-dev/pqtype"
 )
 
-func (m *ActionParser) CreateActionsForMessage(ctx context.Context, actionSets map[string]actions.ActionSet, derivedPerms actions.ActionDerivedPermissions, messageID string, ephemeral bool) error {
+func (m *ActionParser) CreateActionsForMessage(ctx context.Context, actionSets map[string]actions.ActionSet, derivedPerms actions.ActionDerivedPermissions, guildID string, messageID string, ephemeral bool) error {
 	err := m.pg.Q.DeleteMessageActionSetsForMessage(ctx, messageID)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to delete message action sets")
@@ -36,6 +37,7 @@ func (m *ActionParser) CreateActionsForMessage(ctx context.Context, actionSets m
 			Actions:            raw,
 			DerivedPermissions: pqtype.NullRawMessage{Valid: true, RawMessage: rawDerivedPerms},
 			Ephemeral:          ephemeral,
+			GuildID:            sql.NullString{String: guildID, Valid: guildID != ""},
 		})
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to insert message action set")
@@ -0,0 +1,73 @@
+package template
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"time"
+
+	"github.com/botlabs-gg/yagpdb/v2/lib/template"
+	"github.com/jellydator/ttlcache/v3"
+)
+
+const compiledCacheCapacity = 10000
+const compiledCacheTTL = 1 * time.Hour
+
+// compiledCache holds parsed templates so that frequently used messages don't have to be parsed on every interaction.
+// Cached templates must never be executed directly, they are cloned and bound to the functions of the current context instead.
+var compiledCache = ttlcache.New(
+	ttlcache.WithTTL[string, *template.Template](compiledCacheTTL),
+	ttlcache.WithCapacity[string, *template.Template](compiledCacheCapacity),
+)
+
+func init() {
+	go compiledCache.Start()
+}
+
+type CompiledCacheStats struct {
+	Size      int
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	HitRate   float64
+}
+
+// GetCompiledCacheStats returns the metrics of the compiled template cache since the start of the process.
+func GetCompiledCacheStats() CompiledCacheStats {
+	metrics := compiledCache.Metrics()
+
+	stats := CompiledCacheStats{
+		Size:      compiledCache.Len(),
+		Hits:      metrics.Hits,
+		Misses:    metrics.Misses,
+		Evictions: metrics.Evictions,
+	}
+	if total := metrics.Hits + metrics.Misses; total > 0 {
+		stats.HitRate = float64(metrics.Hits) / float64(total)
+	}
+
+	return stats
+}
+
+// funcsVersion identifies the set of available function names.
+// Templates that were parsed with a different set of functions may not be valid in the current context.
+func funcsVersion(funcs map[string]interface{}) uint64 {
+	names := make([]string, 0, len(funcs))
+	for name := range funcs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	h := fnv.New64a()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+func compiledCacheKey(name string, funcsVersion uint64, text string) string {
+	hash := sha256.Sum256([]byte(text))
+	return fmt.Sprintf("%s:%x:%x", name, funcsVersion, hash)
+}
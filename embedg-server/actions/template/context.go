@@ -2,57 +2,103 @@ package template
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"maps"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/botlabs-gg/yagpdb/v2/lib/template"
+	"github.com/jellydator/ttlcache/v3"
 	"github.com/merlinfuchs/embed-generator/embedg-server/actions"
 )
 
 const DefaultMaxOps = 10000
+
+// FunctionOpsShare is the divisor of the remaining operations that functions like lookups can use during an execution.
+const FunctionOpsShare = 2
 const DefaultMaxOutput = 4000
 
+// DefaultTimeout leaves enough time to respond to an interaction within Discord's 3 second deadline.
+const DefaultTimeout = 2500 * time.Millisecond
+
 const DelimLeft = "{{"
 const DelimRight = "}}"
 
+var ErrExecutionTimeout = errors.New("template execution took too long and was aborted")
+
 type TemplateContext struct {
 	name  string
 	data  map[string]interface{}
 	funcs map[string]interface{}
+	exec  *execution
+
+	builder *messageBuilder
+
+	funcsVersion uint64
 
 	MaxOps    int
 	MaxOutput int64
 }
 
-func NewContext(name string, maxOps int, providers ...ContextProvider) *TemplateContext {
+// NewContext creates a template context whose execution is aborted once ctx is done.
+func NewContext(ctx context.Context, name string, maxOps int, providers ...ContextProvider) *TemplateContext {
 	data := make(map[string]interface{}, len(standardDataMap))
 	maps.Copy(data, standardDataMap)
 
 	funcs := make(map[string]interface{}, len(standardFuncMap))
 	maps.Copy(funcs, standardFuncMap)
 
+	if maxOps == 0 {
+		maxOps = DefaultMaxOps
+	}
+
+	exec := &execution{
+		ctx: ctx,
+		ops: &opsCounter{max: maxOps},
+	}
+
+	builder := &messageBuilder{}
+	builder.ProvideFuncs(funcs)
+
 	for _, provider := range providers {
+		if p, ok := provider.(executionAwareProvider); ok {
+			p.setExecution(exec)
+		}
+
 		provider.ProvideData(data)
 		provider.ProvideFuncs(funcs)
 	}
 
-	if maxOps == 0 {
-		maxOps = DefaultMaxOps
-	}
-
-	return &TemplateContext{
+	c := &TemplateContext{
 		name:  name,
 		data:  data,
 		funcs: funcs,
+		exec:  exec,
+
+		builder:      builder,
+		funcsVersion: funcsVersion(funcs),
 
 		MaxOps:    maxOps,
 		MaxOutput: DefaultMaxOutput,
 	}
+
+	for _, provider := range providers {
+		if p, ok := provider.(templateAwareProvider); ok {
+			p.setTemplateContext(c)
+		}
+	}
+
+	return c
 }
 
 func (c *TemplateContext) ParseAndExecuteMessage(m *actions.MessageWithActions) error {
+	c.builder.begin()
+	defer c.builder.end()
+
 	var err error
 
 	m.Content, err = c.ParseAndExecute(m.Content)
@@ -165,7 +211,7 @@ func (c *TemplateContext) ParseAndExecuteMessage(m *actions.MessageWithActions)
 		}
 	}
 
-	return nil
+	return c.builder.apply(m)
 }
 
 func (c *TemplateContext) ParseAndExecute(text string) (string, error) {
@@ -182,20 +228,78 @@ func (c *TemplateContext) ParseAndExecute(text string) (string, error) {
 }
 
 func (c *TemplateContext) Parse(text string) (*template.Template, error) {
-	return template.New(c.name).
-		Delims(DelimLeft, DelimRight).
-		Funcs(c.funcs).
-		Parse(text)
+	key := compiledCacheKey(c.name, c.funcsVersion, text)
+
+	tmpl := compiledCache.Get(key)
+	if tmpl == nil {
+		parsed, err := template.New(c.name).
+			Delims(DelimLeft, DelimRight).
+			Funcs(c.funcs).
+			Parse(text)
+		if err != nil {
+			return nil, err
+		}
+
+		tmpl = compiledCache.Set(key, parsed, ttlcache.DefaultTTL)
+	}
+
+	// The cached template is bound to the functions of the context that parsed it
+	clone, err := tmpl.Value().Clone()
+	if err != nil {
+		return nil, err
+	}
+	return clone.Funcs(c.funcs), nil
 }
 
 func (c *TemplateContext) Execute(tmpl *template.Template) (string, error) {
-	tmpl = tmpl.MaxOps(c.MaxOps)
+	return c.execute(tmpl, c.data)
+}
+
+func (c *TemplateContext) execute(tmpl *template.Template, data interface{}) (string, error) {
+	// Operations used by functions like Discord lookups are deducted from the budget of the template engine
+	remainingOps := c.MaxOps - c.exec.ops.Used()
+	if remainingOps <= 0 {
+		return "", fmt.Errorf("template exceeded the maximum of %d operations", c.MaxOps)
+	}
+
+	// The engine doesn't report how many operations it used, so its share of the remaining budget is reserved
+	// while it runs. Functions can only use the other share, which caps the total at the remaining budget.
+	engineOps := remainingOps - remainingOps/FunctionOpsShare
+	c.exec.ops.reserve(engineOps)
+	defer c.exec.ops.release(engineOps)
+
+	return c.executeWithOps(tmpl, data, engineOps)
+}
+
+// executeWithOps executes the template with a budget of maxOps operations for the template engine.
+func (c *TemplateContext) executeWithOps(tmpl *template.Template, data interface{}, maxOps int) (string, error) {
+	if err := c.exec.ctx.Err(); err != nil {
+		return "", c.contextError(err)
+	}
+
+	tmpl = tmpl.MaxOps(maxOps)
 
 	var buf bytes.Buffer
-	w := LimitWriter(&buf, c.MaxOutput)
+	w := ContextWriter(c.exec.ctx, LimitWriter(&buf, c.MaxOutput))
+
+	// The template engine can't be interrupted, so we stop waiting for it when the context is done.
+	// It will stop by itself soon after because all functions and writes fail from that point on.
+	done := make(chan error, 1)
+	go func() {
+		done <- tmpl.Execute(w, data)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-c.exec.ctx.Done():
+		return "", c.contextError(c.exec.ctx.Err())
+	}
 
-	err := tmpl.Execute(w, c.data)
 	if err != nil {
+		if ctxErr := c.exec.ctx.Err(); ctxErr != nil {
+			return "", c.contextError(ctxErr)
+		}
 		if err == io.ErrShortWrite {
 			err = fmt.Errorf("output exceeded %d characters", c.MaxOutput)
 		}
@@ -207,6 +311,77 @@ func (c *TemplateContext) Execute(tmpl *template.Template) (string, error) {
 	return res, nil
 }
 
+func (c *TemplateContext) contextError(err error) error {
+	if err == context.DeadlineExceeded {
+		return ErrExecutionTimeout
+	}
+	return err
+}
+
 func (c *TemplateContext) Set(key string, value interface{}) {
 	c.data[key] = value
 }
+
+// opsCounter keeps track of operations that are performed by template functions instead of the template engine.
+type opsCounter struct {
+	sync.Mutex
+	used int
+	max  int
+}
+
+func (c *opsCounter) Add(n int) error {
+	c.Lock()
+	defer c.Unlock()
+
+	c.used += n
+	if c.used > c.max {
+		return fmt.Errorf("template exceeded the maximum of %d operations", c.max)
+	}
+	return nil
+}
+
+// reserve counts n operations as used until they are released again, it doesn't fail if that exceeds the maximum.
+func (c *opsCounter) reserve(n int) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.used += n
+}
+
+func (c *opsCounter) release(n int) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.used -= n
+}
+
+func (c *opsCounter) Used() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.used
+}
+
+// execution holds the state that is shared between the template context and the functions of its providers.
+type execution struct {
+	ctx context.Context
+	ops *opsCounter
+}
+
+// context returns the context that functions should use for I/O, it's safe to call on a nil execution.
+func (e *execution) context() context.Context {
+	if e == nil {
+		return context.Background()
+	}
+	return e.ctx
+}
+
+// executionAwareProvider is implemented by providers whose functions need access to the execution state of the context.
+type executionAwareProvider interface {
+	setExecution(exec *execution)
+}
+
+// templateAwareProvider is implemented by providers whose functions need to parse and execute templates in the context.
+type templateAwareProvider interface {
+	setTemplateContext(c *TemplateContext)
+}
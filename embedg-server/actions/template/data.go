@@ -23,8 +23,7 @@ func NewInteractionData(state *discordgo.State, i *discordgo.Interaction) *Inter
 
 func (d *InteractionData) User() interface{} {
 	if d.i.Member != nil {
-		res := NewMemberData(d.state, d.i.GuildID, d.i.Member)
-		return &res
+		return NewMemberData(d.state, d.i.GuildID, d.i.Member)
 	}
 
 	return NewUserData(d.i.User)
@@ -39,7 +38,7 @@ func (d *InteractionData) Member() *MemberData {
 }
 
 func (d *InteractionData) Command() *CommandData {
-	if d.i.Type != discordgo.InteractionApplicationCommand {
+	if d.i.Type != discordgo.InteractionApplicationCommand && d.i.Type != discordgo.InteractionApplicationCommandAutocomplete {
 		return nil
 	}
 
@@ -47,6 +46,74 @@ func (d *InteractionData) Command() *CommandData {
 	return NewCommandData(d.state, d.i.GuildID, &data)
 }
 
+// Target returns the user or message that a context menu command was used on.
+// Users are returned as members when the command was used in a guild.
+func (d *InteractionData) Target() interface{} {
+	if d.i.Type != discordgo.InteractionApplicationCommand {
+		return nil
+	}
+
+	data := d.i.ApplicationCommandData()
+	if data.TargetID == "" || data.Resolved == nil {
+		return nil
+	}
+
+	switch data.CommandType {
+	case discordgo.UserApplicationCommand:
+		user := data.Resolved.Users[data.TargetID]
+		if user == nil {
+			return nil
+		}
+
+		if member := data.Resolved.Members[data.TargetID]; member != nil {
+			// Resolved members don't include the user
+			m := *member
+			m.User = user
+			return NewMemberData(d.state, d.i.GuildID, &m)
+		}
+		return NewUserData(user)
+	case discordgo.MessageApplicationCommand:
+		message := data.Resolved.Messages[data.TargetID]
+		if message == nil {
+			return nil
+		}
+		return NewMessageData(d.state, d.i.GuildID, message)
+	}
+
+	return nil
+}
+
+func (d *InteractionData) Message() *MessageData {
+	if d.i.Message == nil {
+		return nil
+	}
+
+	return NewMessageData(d.state, d.i.GuildID, d.i.Message)
+}
+
+func (d *InteractionData) Component() *ComponentData {
+	if d.i.Type != discordgo.InteractionMessageComponent || d.i.Data == nil {
+		return nil
+	}
+
+	data := d.i.MessageComponentData()
+	return NewComponentData(d.state, d.i.GuildID, d.i.Message, &data)
+}
+
+// Locale returns the locale that the user who triggered the interaction has selected in Discord.
+func (d *InteractionData) Locale() string {
+	return string(d.i.Locale)
+}
+
+// GuildLocale returns the preferred locale of the guild the interaction was triggered in.
+func (d *InteractionData) GuildLocale() string {
+	if d.i.GuildLocale == nil {
+		return ""
+	}
+
+	return string(*d.i.GuildLocale)
+}
+
 type UserData struct {
 	u *discordgo.User
 }
@@ -190,13 +257,27 @@ func (d *CommandData) Mention() string {
 	return fmt.Sprintf("</%s:%s>", d.c.Name, d.c.ID)
 }
 
+// Options returns the values of the options by name, optional options that weren't provided are missing.
+// Options of subcommands are returned as if they belonged to the command itself.
 func (d *CommandData) Options() map[string]interface{} {
 	res := make(map[string]interface{})
-	for _, opt := range d.c.Options {
+	d.addOptions(res, d.c.Options)
+	return res
+}
+
+func (d *CommandData) addOptions(res map[string]interface{}, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	for _, opt := range options {
+		if opt.Type == discordgo.ApplicationCommandOptionSubCommand || opt.Type == discordgo.ApplicationCommandOptionSubCommandGroup {
+			d.addOptions(res, opt.Options)
+			continue
+		}
+		if opt.Focused {
+			// The option that is being autocompleted only contains the text that has been typed so far
+			res[opt.Name] = fmt.Sprint(opt.Value)
+			continue
+		}
 		res[opt.Name] = NewCommandOptionData(d.state, d.guildID, d.c, opt)
 	}
-
-	return res
 }
 
 func (d *CommandData) Args() map[string]interface{} {
@@ -204,6 +285,12 @@ func (d *CommandData) Args() map[string]interface{} {
 }
 
 func NewCommandOptionData(state *discordgo.State, guildID string, c *discordgo.ApplicationCommandInteractionData, o *discordgo.ApplicationCommandInteractionDataOption) interface{} {
+	// Autocomplete interactions don't always include resolved data
+	r := c.Resolved
+	if r == nil {
+		r = &discordgo.ApplicationCommandInteractionDataResolved{}
+	}
+
 	switch o.Type {
 	case discordgo.ApplicationCommandOptionString:
 		return o.StringValue()
@@ -213,21 +300,21 @@ func NewCommandOptionData(state *discordgo.State, guildID string, c *discordgo.A
 		return o.BoolValue()
 	case discordgo.ApplicationCommandOptionUser:
 		user := o.UserValue(nil)
-		resolved := c.Resolved.Users[user.ID]
+		resolved := r.Users[user.ID]
 		if resolved != nil {
-			return UserData{resolved}
+			return NewUserData(resolved)
 		}
-		return UserData{user}
+		return NewUserData(user)
 	case discordgo.ApplicationCommandOptionChannel:
 		channel := o.ChannelValue(nil)
-		resolved := c.Resolved.Channels[channel.ID]
+		resolved := r.Channels[channel.ID]
 		if resolved != nil {
 			return NewChannelData(state, channel.ID, resolved)
 		}
 		return NewChannelData(state, channel.ID, nil)
 	case discordgo.ApplicationCommandOptionRole:
 		role := o.RoleValue(nil, "")
-		resolved := c.Resolved.Roles[role.ID]
+		resolved := r.Roles[role.ID]
 		if resolved != nil {
 			return NewRoleData(state, guildID, role.ID, resolved)
 		}
@@ -235,7 +322,7 @@ func NewCommandOptionData(state *discordgo.State, guildID string, c *discordgo.A
 	case discordgo.ApplicationCommandOptionNumber:
 		return fmt.Sprintf("%f", o.FloatValue())
 	case discordgo.ApplicationCommandOptionAttachment:
-		attachment := c.Resolved.Attachments[o.Value.(string)]
+		attachment := r.Attachments[o.Value.(string)]
 		if attachment != nil {
 			return NewAttachmentData(attachment)
 		}
@@ -357,6 +444,14 @@ func (d *GuildData) BoostLevel() (int, error) {
 	return int(d.guild.PremiumTier), nil
 }
 
+func (d *GuildData) Locale() (string, error) {
+	if err := d.ensureGuild(); err != nil {
+		return "", err
+	}
+
+	return d.guild.PreferredLocale, nil
+}
+
 type ChannelData struct {
 	state     *discordgo.State
 	channelID string
@@ -482,3 +577,312 @@ func (d *AttachmentData) ID() string {
 func (d *AttachmentData) URL() string {
 	return d.a.URL
 }
+
+type MessageData struct {
+	state   *discordgo.State
+	guildID string
+	m       *discordgo.Message
+}
+
+func NewMessageData(state *discordgo.State, guildID string, m *discordgo.Message) *MessageData {
+	return &MessageData{
+		state:   state,
+		guildID: guildID,
+		m:       m,
+	}
+}
+
+func (d *MessageData) String() string {
+	return d.Link()
+}
+
+func (d *MessageData) ID() string {
+	return d.m.ID
+}
+
+func (d *MessageData) ChannelID() string {
+	return d.m.ChannelID
+}
+
+func (d *MessageData) Channel() *ChannelData {
+	return NewChannelData(d.state, d.m.ChannelID, nil)
+}
+
+func (d *MessageData) Content() string {
+	return d.m.Content
+}
+
+func (d *MessageData) Author() *UserData {
+	if d.m.Author == nil {
+		return nil
+	}
+
+	return NewUserData(d.m.Author)
+}
+
+func (d *MessageData) Embeds() []*EmbedData {
+	res := make([]*EmbedData, len(d.m.Embeds))
+	for i, embed := range d.m.Embeds {
+		res[i] = NewEmbedData(embed)
+	}
+
+	return res
+}
+
+func (d *MessageData) CreatedAt() time.Time {
+	return d.m.Timestamp
+}
+
+func (d *MessageData) Link() string {
+	guildID := d.guildID
+	if guildID == "" {
+		guildID = "@me"
+	}
+
+	return fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, d.m.ChannelID, d.m.ID)
+}
+
+type EmbedData struct {
+	e *discordgo.MessageEmbed
+}
+
+func NewEmbedData(e *discordgo.MessageEmbed) *EmbedData {
+	return &EmbedData{e: e}
+}
+
+func (d *EmbedData) Title() string {
+	return d.e.Title
+}
+
+func (d *EmbedData) Description() string {
+	return d.e.Description
+}
+
+func (d *EmbedData) URL() string {
+	return d.e.URL
+}
+
+func (d *EmbedData) Color() int {
+	return d.e.Color
+}
+
+func (d *EmbedData) AuthorName() string {
+	if d.e.Author == nil {
+		return ""
+	}
+
+	return d.e.Author.Name
+}
+
+func (d *EmbedData) FooterText() string {
+	if d.e.Footer == nil {
+		return ""
+	}
+
+	return d.e.Footer.Text
+}
+
+func (d *EmbedData) ImageURL() string {
+	if d.e.Image == nil {
+		return ""
+	}
+
+	return d.e.Image.URL
+}
+
+func (d *EmbedData) ThumbnailURL() string {
+	if d.e.Thumbnail == nil {
+		return ""
+	}
+
+	return d.e.Thumbnail.URL
+}
+
+func (d *EmbedData) Fields() []*EmbedFieldData {
+	res := make([]*EmbedFieldData, len(d.e.Fields))
+	for i, field := range d.e.Fields {
+		res[i] = &EmbedFieldData{f: field}
+	}
+
+	return res
+}
+
+type EmbedFieldData struct {
+	f *discordgo.MessageEmbedField
+}
+
+func (d *EmbedFieldData) Name() string {
+	return d.f.Name
+}
+
+func (d *EmbedFieldData) Value() string {
+	return d.f.Value
+}
+
+func (d *EmbedFieldData) Inline() bool {
+	return d.f.Inline
+}
+
+type ComponentData struct {
+	state   *discordgo.State
+	guildID string
+	m       *discordgo.Message
+	c       *discordgo.MessageComponentInteractionData
+}
+
+func NewComponentData(state *discordgo.State, guildID string, m *discordgo.Message, c *discordgo.MessageComponentInteractionData) *ComponentData {
+	return &ComponentData{
+		state:   state,
+		guildID: guildID,
+		m:       m,
+		c:       c,
+	}
+}
+
+func (d *ComponentData) String() string {
+	return d.Label()
+}
+
+func (d *ComponentData) CustomID() string {
+	return d.c.CustomID
+}
+
+func (d *ComponentData) Type() string {
+	switch d.c.ComponentType {
+	case discordgo.ButtonComponent:
+		return "button"
+	case discordgo.SelectMenuComponent:
+		return "string_select"
+	case discordgo.UserSelectMenuComponent:
+		return "user_select"
+	case discordgo.RoleSelectMenuComponent:
+		return "role_select"
+	case discordgo.MentionableSelectMenuComponent:
+		return "mentionable_select"
+	case discordgo.ChannelSelectMenuComponent:
+		return "channel_select"
+	}
+
+	return fmt.Sprintf("%d", d.c.ComponentType)
+}
+
+// Label returns the label of the clicked button or the placeholder of the select menu.
+func (d *ComponentData) Label() string {
+	switch c := d.component().(type) {
+	case *discordgo.Button:
+		return c.Label
+	case *discordgo.SelectMenu:
+		return c.Placeholder
+	}
+
+	return ""
+}
+
+// Values returns the raw values of the selected options, users, roles or channels.
+func (d *ComponentData) Values() []string {
+	return d.c.Values
+}
+
+func (d *ComponentData) Value() string {
+	if len(d.c.Values) == 0 {
+		return ""
+	}
+
+	return d.c.Values[0]
+}
+
+// Selected returns the labels of the selected options of a string select menu.
+func (d *ComponentData) Selected() []string {
+	menu, ok := d.component().(*discordgo.SelectMenu)
+	if !ok {
+		return nil
+	}
+
+	res := make([]string, 0, len(d.c.Values))
+	for _, value := range d.c.Values {
+		for _, option := range menu.Options {
+			if option.Value == value {
+				res = append(res, option.Label)
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+func (d *ComponentData) Users() []*UserData {
+	res := make([]*UserData, 0, len(d.c.Values))
+	for _, value := range d.c.Values {
+		if user, ok := d.c.Resolved.Users[value]; ok {
+			res = append(res, NewUserData(user))
+		}
+	}
+
+	return res
+}
+
+func (d *ComponentData) Roles() []*RoleData {
+	res := make([]*RoleData, 0, len(d.c.Values))
+	for _, value := range d.c.Values {
+		if role, ok := d.c.Resolved.Roles[value]; ok {
+			res = append(res, NewRoleData(d.state, d.guildID, value, role))
+		}
+	}
+
+	return res
+}
+
+func (d *ComponentData) Channels() []*ChannelData {
+	res := make([]*ChannelData, 0, len(d.c.Values))
+	for _, value := range d.c.Values {
+		if channel, ok := d.c.Resolved.Channels[value]; ok {
+			res = append(res, NewChannelData(d.state, value, channel))
+		}
+	}
+
+	return res
+}
+
+// component finds the clicked component on the message by its custom ID.
+func (d *ComponentData) component() discordgo.MessageComponent {
+	if d.m == nil {
+		return nil
+	}
+
+	return findComponent(d.m.Components, d.c.CustomID)
+}
+
+func findComponent(components []discordgo.MessageComponent, customID string) discordgo.MessageComponent {
+	for _, component := range components {
+		switch c := component.(type) {
+		case *discordgo.Button:
+			if c.CustomID == customID {
+				return c
+			}
+		case *discordgo.SelectMenu:
+			if c.CustomID == customID {
+				return c
+			}
+		case *discordgo.ActionsRow:
+			if found := findComponent(c.Components, customID); found != nil {
+				return found
+			}
+		case *discordgo.Section:
+			if found := findComponent(c.Components, customID); found != nil {
+				return found
+			}
+			if c.Accessory != nil {
+				if found := findComponent([]discordgo.MessageComponent{c.Accessory}, customID); found != nil {
+					return found
+				}
+			}
+		case *discordgo.Container:
+			if found := findComponent(c.Components, customID); found != nil {
+				return found
+			}
+		}
+	}
+
+	return nil
+}
@@ -92,6 +92,17 @@ var standardFuncMap = map[string]interface{}{
 	"newDate":         tmplNewDate,
 	"timestampToTime": tmplTimestampToTime,
 	"weekNumber":      tmplWeekNumber,
+
+	// discord formatting
+	"timestamp":        tmplTimestamp,
+	"mentionRole":      tmplMentionRole,
+	"mentionChannel":   tmplMentionChannel,
+	"spoiler":          tmplSpoiler,
+	"codeBlock":        tmplCodeBlock,
+	"escapeMarkdown":   tmplEscapeMarkdown,
+	"truncate":         tmplTruncate,
+	"progressBar":      tmplProgressBar,
+	"humanizeDuration": tmplHumanizeDuration,
 }
 
 // dictionary creates a map[string]interface{} from the given parameters by
@@ -1200,3 +1211,161 @@ func tmplWeekNumber(t time.Time) (week int) {
 	_, week = t.ISOWeek()
 	return
 }
+
+const timestampStyles = "tTdDfFR"
+
+// tmplTimestamp formats the time as a Discord timestamp that is displayed in the local time of the user.
+// The time can be a time.Time, a unix timestamp in seconds or an RFC3339 string.
+func tmplTimestamp(v interface{}, style ...string) (string, error) {
+	var unix int64
+	switch t := v.(type) {
+	case time.Time:
+		unix = t.Unix()
+	case *time.Time:
+		if t == nil {
+			return "", errors.New("timestamp: nil time passed")
+		}
+		unix = t.Unix()
+	case string:
+		parsed, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			seconds, serr := strconv.ParseInt(t, 10, 64)
+			if serr != nil {
+				return "", fmt.Errorf("timestamp: invalid time %q", t)
+			}
+			unix = seconds
+		} else {
+			unix = parsed.Unix()
+		}
+	default:
+		unix = ToInt64(v)
+	}
+
+	if len(style) == 0 || style[0] == "" {
+		return fmt.Sprintf("<t:%d>", unix), nil
+	}
+
+	if len(style[0]) != 1 || !strings.Contains(timestampStyles, style[0]) {
+		return "", fmt.Errorf("timestamp: invalid style %q, must be one of %s", style[0], strings.Join(strings.Split(timestampStyles, ""), ", "))
+	}
+
+	return fmt.Sprintf("<t:%d:%s>", unix, style[0]), nil
+}
+
+func tmplMentionRole(id interface{}) string {
+	return "<@&" + toEntityID(id) + ">"
+}
+
+func tmplMentionChannel(id interface{}) string {
+	return "<#" + toEntityID(id) + ">"
+}
+
+func tmplSpoiler(s interface{}) string {
+	return "||" + ToString(s) + "||"
+}
+
+// tmplCodeBlock wraps the text in a code block, closing fences inside the text are broken up with a zero-width space.
+func tmplCodeBlock(lang string, s interface{}) string {
+	text := strings.ReplaceAll(ToString(s), "```", "`\u200b``")
+	return "```" + lang + "\n" + text + "\n```"
+}
+
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"`", "\\`",
+	"|", `\|`,
+	">", `\>`,
+	"#", `\#`,
+	"[", `\[`,
+	"]", `\]`,
+	"(", `\(`,
+	")", `\)`,
+)
+
+func tmplEscapeMarkdown(s interface{}) string {
+	return markdownReplacer.Replace(ToString(s))
+}
+
+// tmplTruncate shortens the text to at most n characters and ends it with an ellipsis if anything was cut off.
+func tmplTruncate(n int, s interface{}) string {
+	runes := []rune(ToString(s))
+	if len(runes) <= n {
+		return string(runes)
+	}
+	if n <= 0 {
+		return ""
+	}
+
+	return string(runes[:n-1]) + "…"
+}
+
+const maxProgressBarWidth = 100
+
+func tmplProgressBar(value interface{}, max interface{}, width ...int) (string, error) {
+	w := 10
+	if len(width) > 0 {
+		w = width[0]
+	}
+	if w <= 0 || w > maxProgressBarWidth {
+		return "", fmt.Errorf("progressBar: width must be between 1 and %d", maxProgressBarWidth)
+	}
+
+	m := ToFloat64(max)
+	if m <= 0 {
+		return "", errors.New("progressBar: max must be greater than 0")
+	}
+
+	ratio := math.Min(math.Max(ToFloat64(value)/m, 0), 1)
+	filled := int(math.Round(ratio * float64(w)))
+
+	return strings.Repeat("█", filled) + strings.Repeat("░", w-filled), nil
+}
+
+var durationUnits = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"day", 24 * time.Hour},
+	{"hour", time.Hour},
+	{"minute", time.Minute},
+	{"second", time.Second},
+}
+
+// tmplHumanizeDuration formats a duration like "2 days, 3 hours and 5 seconds".
+// The duration can be a time.Duration, a duration string like "1h30m" or a number of seconds.
+func tmplHumanizeDuration(v interface{}) (string, error) {
+	d, err := toDuration(v)
+	if err != nil {
+		return "", fmt.Errorf("humanizeDuration: %w", err)
+	}
+	if d < 0 {
+		d = -d
+	}
+
+	var parts []string
+	for _, unit := range durationUnits {
+		count := d / unit.duration
+		if count == 0 {
+			continue
+		}
+		d -= count * unit.duration
+
+		part := fmt.Sprintf("%d %s", count, unit.name)
+		if count != 1 {
+			part += "s"
+		}
+		parts = append(parts, part)
+	}
+
+	switch len(parts) {
+	case 0:
+		return "0 seconds", nil
+	case 1:
+		return parts[0], nil
+	default:
+		return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1], nil
+	}
+}
@@ -0,0 +1,323 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+	"github.com/merlinfuchs/embed-generator/embedg-server/store"
+	"github.com/spf13/viper"
+)
+
+// MaxHTTPRequests is the maximum number of requests that a single context can send.
+const MaxHTTPRequests = 5
+
+// HTTPRequestOps is the number of template operations that fetching a URL counts as.
+const HTTPRequestOps = 500
+
+// HTTPRequestTimeout is the time a single request can take, it's further limited by the deadline of the context.
+const HTTPRequestTimeout = 2 * time.Second
+
+// MaxHTTPResponseSize is the maximum size of a response body in bytes.
+const MaxHTTPResponseSize = 64 * 1024
+
+const maxHTTPRedirects = 3
+
+const httpCacheCapacity = 1000
+const httpCacheTTL = 1 * time.Minute
+
+// httpCache holds successful responses by URL so that frequently rendered messages don't hit the same URL every time.
+// It's shared by all guilds, which is fine because the allowlist of the guild is checked before the cache is used.
+var httpCache = ttlcache.New(
+	ttlcache.WithTTL[string, []byte](httpCacheTTL),
+	ttlcache.WithCapacity[string, []byte](httpCacheCapacity),
+)
+
+func init() {
+	go httpCache.Start()
+}
+
+var errPrivateNetwork = errors.New("connecting to private networks is not allowed")
+
+// NewHTTPClient creates the client that is used for requests from templates.
+// Unless private networks are allowed, it refuses to connect to them so that allowlisted hosts can't resolve to internal services.
+func NewHTTPClient(allowPrivateNetworks bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: HTTPRequestTimeout,
+	}
+	if !allowPrivateNetworks {
+		dialer.Control = func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicIP(ip) {
+				return errPrivateNetwork
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: HTTPRequestTimeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   HTTPRequestTimeout,
+			ResponseHeaderTimeout: HTTPRequestTimeout,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+		},
+	}
+}
+
+// defaultHTTPClient is used when no client is passed to the provider.
+// Private networks can be allowed in the config to test templates against a local server.
+var defaultHTTPClient = sync.OnceValue(func() *http.Client {
+	return NewHTTPClient(viper.GetBool("templates.http.allow_private_networks"))
+})
+
+// sharedAddressSpace is used for carrier-grade NAT and by some cloud providers for internal services.
+var sharedAddressSpace = &net.IPNet{IP: net.IP{100, 64, 0, 0}, Mask: net.CIDRMask(10, 32)}
+
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!sharedAddressSpace.Contains(ip) &&
+		!ip.IsPrivate() &&
+		!ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast()
+}
+
+// HTTPProvider provides the httpGet and httpJSON functions which fetch URLs from the allowlisted hosts of the guild.
+type HTTPProvider struct {
+	sync.Mutex
+	guildID   string
+	hostStore store.TemplateHTTPHostStore
+	client    *http.Client
+	enabled   bool
+	cacheOnly bool
+	exec      *execution
+
+	loaded   bool
+	hosts    []string
+	requests int
+}
+
+// NewHTTPProvider creates the provider, client can be nil to use the default client.
+// When the plan of the guild doesn't include HTTP requests, the functions are still available but always fail.
+func NewHTTPProvider(guildID string, hostStore store.TemplateHTTPHostStore, client *http.Client, enabled bool) *HTTPProvider {
+	if client == nil {
+		client = defaultHTTPClient()
+	}
+
+	p := &HTTPProvider{
+		guildID:   guildID,
+		hostStore: hostStore,
+		enabled:   enabled,
+	}
+
+	// Redirects must not lead away from the allowlisted hosts
+	c := *client
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > maxHTTPRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxHTTPRedirects)
+		}
+		return p.checkURL(req.URL)
+	}
+	p.client = &c
+
+	return p
+}
+
+// WithCacheOnly makes the provider only return responses that are already cached instead of sending requests.
+func (p *HTTPProvider) WithCacheOnly() *HTTPProvider {
+	p.cacheOnly = true
+	return p
+}
+
+func (p *HTTPProvider) setExecution(exec *execution) {
+	p.exec = exec
+}
+
+func (p *HTTPProvider) ProvideFuncs(funcs map[string]interface{}) {
+	funcs["httpGet"] = p.httpGet
+	funcs["httpJSON"] = p.httpJSON
+}
+
+func (p *HTTPProvider) ProvideData(data map[string]interface{}) {}
+
+// httpGet returns the body of the response as a string.
+func (p *HTTPProvider) httpGet(rawURL string) (string, error) {
+	body, err := p.fetch(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("httpGet: %w", err)
+	}
+	return string(body), nil
+}
+
+// httpJSON returns the decoded JSON body of the response.
+func (p *HTTPProvider) httpJSON(rawURL string) (interface{}, error) {
+	body, err := p.fetch(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("httpJSON: %w", err)
+	}
+
+	var res interface{}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("httpJSON: response is not valid JSON: %w", err)
+	}
+	return res, nil
+}
+
+func (p *HTTPProvider) fetch(rawURL string) ([]byte, error) {
+	if !p.enabled {
+		return nil, fmt.Errorf("HTTP requests are not available on the plan of this server")
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if err := p.checkURL(u); err != nil {
+		return nil, err
+	}
+
+	if err := p.exec.ops.Add(HTTPRequestOps); err != nil {
+		return nil, err
+	}
+
+	key := u.String()
+	if item := httpCache.Get(key); item != nil {
+		return item.Value(), nil
+	}
+
+	if p.cacheOnly {
+		return nil, fmt.Errorf("%s hasn't been requested recently, only cached responses are available here", key)
+	}
+
+	if err := p.countRequest(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(p.exec.context(), HTTPRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "EmbedGenerator (https://message.style)")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("request to %s timed out", u.Host)
+		}
+		return nil, fmt.Errorf("request to %s failed: %w", u.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s responded with status %d", u.Host, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxHTTPResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", u.Host, err)
+	}
+	if len(body) > MaxHTTPResponseSize {
+		return nil, fmt.Errorf("response from %s is larger than %d bytes", u.Host, MaxHTTPResponseSize)
+	}
+
+	httpCache.Set(key, body, ttlcache.DefaultTTL)
+	return body, nil
+}
+
+// checkURL returns an error if the URL can't be requested by the guild.
+func (p *HTTPProvider) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("only http and https URLs are supported")
+	}
+	if u.User != nil {
+		return fmt.Errorf("URLs with credentials are not supported")
+	}
+
+	hosts, err := p.load()
+	if err != nil {
+		return err
+	}
+
+	if !HTTPHostAllowed(hosts, u.Hostname()) {
+		return fmt.Errorf("host %s is not on the allowlist of this server", u.Hostname())
+	}
+	return nil
+}
+
+func (p *HTTPProvider) countRequest() error {
+	p.Lock()
+	defer p.Unlock()
+
+	p.requests++
+	if p.requests > MaxHTTPRequests {
+		return fmt.Errorf("a message can't send more than %d requests", MaxHTTPRequests)
+	}
+	return nil
+}
+
+// load fetches the allowlisted hosts of the guild once per context.
+func (p *HTTPProvider) load() ([]string, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.loaded {
+		return p.hosts, nil
+	}
+
+	hosts, err := p.hostStore.GetTemplateHTTPHosts(p.exec.context(), p.guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load allowed hosts: %w", err)
+	}
+
+	p.hosts = make([]string, len(hosts))
+	for i, host := range hosts {
+		p.hosts[i] = host.Host
+	}
+	p.loaded = true
+
+	return p.hosts, nil
+}
+
+// HTTPHostAllowed returns whether the host matches one of the allowed hosts.
+// An allowed host starting with "*." matches all of its subdomains but not the domain itself.
+func HTTPHostAllowed(allowed []string, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" {
+		return false
+	}
+
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,268 @@
+package template
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/model"
+)
+
+type testHostStore []string
+
+func (s testHostStore) GetTemplateHTTPHosts(ctx context.Context, guildID string) ([]model.TemplateHTTPHost, error) {
+	hosts := make([]model.TemplateHTTPHost, len(s))
+	for i, host := range s {
+		hosts[i] = model.TemplateHTTPHost{GuildID: guildID, Host: host}
+	}
+	return hosts, nil
+}
+
+// newTestHTTPProvider creates a provider whose allowlist contains the host of the server.
+// The client of the server is used because the default client refuses to connect to the loopback address.
+func newTestHTTPProvider(t *testing.T, ctx context.Context, srv *httptest.Server) *HTTPProvider {
+	t.Helper()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewHTTPProvider("1", testHostStore{u.Hostname()}, srv.Client(), true)
+	p.setExecution(&execution{
+		ctx: ctx,
+		ops: &opsCounter{max: 100 * HTTPRequestOps},
+	})
+	return p
+}
+
+// testURL returns a URL of the server that is unique to the test so that responses cached by other tests aren't used.
+func testURL(t *testing.T, srv *httptest.Server, path string) string {
+	return srv.URL + "/" + url.PathEscape(t.Name()) + path
+}
+
+func TestHTTPProviderAllowlist(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("redirect") {
+			http.Redirect(w, r, r.URL.Query().Get("redirect"), http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	p := newTestHTTPProvider(t, context.Background(), srv)
+
+	body, err := p.httpGet(testURL(t, srv, "/allowed"))
+	if err != nil {
+		t.Fatalf("request to allowlisted host failed: %v", err)
+	}
+	if body != "ok" {
+		t.Fatalf("unexpected body %q", body)
+	}
+
+	// localhost resolves to the same server but isn't on the allowlist
+	u, _ := url.Parse(srv.URL)
+	otherHost := "http://localhost:" + u.Port()
+	if _, err := p.httpGet(otherHost + "/" + t.Name()); err == nil || !strings.Contains(err.Error(), "not on the allowlist") {
+		t.Fatalf("expected allowlist error, got %v", err)
+	}
+
+	redirect := testURL(t, srv, "/redirect") + "?redirect=" + url.QueryEscape(otherHost+"/"+t.Name())
+	if _, err := p.httpGet(redirect); err == nil || !strings.Contains(err.Error(), "not on the allowlist") {
+		t.Fatalf("expected redirect to other host to fail, got %v", err)
+	}
+
+	if _, err := p.httpGet("ftp://" + u.Host + "/file"); err == nil {
+		t.Fatal("expected non-http scheme to fail")
+	}
+}
+
+func TestHTTPProviderResponseSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size := MaxHTTPResponseSize
+		if strings.HasSuffix(r.URL.Path, "/large") {
+			size++
+		}
+		w.Write([]byte(strings.Repeat("a", size)))
+	}))
+	defer srv.Close()
+
+	p := newTestHTTPProvider(t, context.Background(), srv)
+
+	body, err := p.httpGet(testURL(t, srv, "/max"))
+	if err != nil {
+		t.Fatalf("response of the maximum size failed: %v", err)
+	}
+	if len(body) != MaxHTTPResponseSize {
+		t.Fatalf("expected %d bytes, got %d", MaxHTTPResponseSize, len(body))
+	}
+
+	if _, err := p.httpGet(testURL(t, srv, "/large")); err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Fatalf("expected size error, got %v", err)
+	}
+}
+
+func TestHTTPProviderTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	p := newTestHTTPProvider(t, ctx, srv)
+
+	start := time.Now()
+	if _, err := p.httpGet(testURL(t, srv, "/slow")); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= HTTPRequestTimeout {
+		t.Fatalf("request wasn't aborted by the deadline of the context, took %s", elapsed)
+	}
+}
+
+func TestHTTPProviderRequestLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	p := newTestHTTPProvider(t, context.Background(), srv)
+
+	for i := 0; i < MaxHTTPRequests; i++ {
+		if _, err := p.httpGet(testURL(t, srv, fmt.Sprintf("/%d", i))); err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+	}
+
+	if _, err := p.httpGet(testURL(t, srv, "/limit")); err == nil || !strings.Contains(err.Error(), "more than") {
+		t.Fatalf("expected request limit error, got %v", err)
+	}
+
+	// The limit is per context
+	other := newTestHTTPProvider(t, context.Background(), srv)
+	if _, err := other.httpGet(testURL(t, srv, "/other")); err != nil {
+		t.Fatalf("request of other context failed: %v", err)
+	}
+}
+
+func TestHTTPProviderCache(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		if strings.HasSuffix(r.URL.Path, "/error") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `{"hit": %d}`, n)
+	}))
+	defer srv.Close()
+
+	p := newTestHTTPProvider(t, context.Background(), srv)
+
+	first, err := p.httpJSON(testURL(t, srv, "/cached"))
+	if err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+
+	other := newTestHTTPProvider(t, context.Background(), srv)
+	second, err := other.httpJSON(testURL(t, srv, "/cached"))
+	if err != nil {
+		t.Fatalf("second request failed: %v", err)
+	}
+
+	if hits.Load() != 1 {
+		t.Fatalf("expected the second request to be served from the cache, server was hit %d times", hits.Load())
+	}
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Fatalf("cached response %v differs from %v", second, first)
+	}
+
+	// Cached responses don't count towards the request limit
+	if p.requests != 1 || other.requests != 0 {
+		t.Fatalf("unexpected request counts %d and %d", p.requests, other.requests)
+	}
+
+	// Providers that only use the cache don't send requests
+	cacheOnly := newTestHTTPProvider(t, context.Background(), srv).WithCacheOnly()
+	if _, err := cacheOnly.httpJSON(testURL(t, srv, "/cached")); err != nil {
+		t.Fatalf("cached request of cache only provider failed: %v", err)
+	}
+	if _, err := cacheOnly.httpGet(testURL(t, srv, "/uncached")); err == nil || !strings.Contains(err.Error(), "only cached responses") {
+		t.Fatalf("expected uncached request of cache only provider to fail, got %v", err)
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("expected the cache only provider to not send requests, server was hit %d times", hits.Load())
+	}
+
+	// Failed responses aren't cached
+	for i := 0; i < 2; i++ {
+		if _, err := p.httpGet(testURL(t, srv, "/error")); err == nil {
+			t.Fatal("expected error status to fail")
+		}
+	}
+	if hits.Load() != 3 {
+		t.Fatalf("expected failed responses to not be cached, server was hit %d times", hits.Load())
+	}
+}
+
+func TestHTTPHostAllowed(t *testing.T) {
+	allowed := []string{"api.example.com", "*.example.org"}
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"api.example.com", true},
+		{"API.Example.com.", true},
+		{"example.com", false},
+		{"other.api.example.com", false},
+		{"a.example.org", true},
+		{"a.b.example.org", true},
+		{"example.org", false},
+		{"badexample.org", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if got := HTTPHostAllowed(allowed, test.host); got != test.want {
+			t.Errorf("HTTPHostAllowed(%q) = %v, want %v", test.host, got, test.want)
+		}
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"1.1.1.1", true},
+		{"100.63.255.255", true},
+		{"100.64.0.1", false},
+		{"100.127.255.255", false},
+		{"100.128.0.0", true},
+		{"127.0.0.1", false},
+		{"10.0.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fd00::1", false},
+		{"2606:4700:4700::1111", true},
+	}
+
+	for _, test := range tests {
+		if got := isPublicIP(net.ParseIP(test.ip)); got != test.want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", test.ip, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/actions"
+)
+
+const maxLintSuggestions = 3
+
+var parseErrorRegex = regexp.MustCompile(`^template: [^:]*:(\d+):(?:(\d+):)? (.*)$`)
+var quotedNameRegex = regexp.MustCompile(`"([^"]+)"`)
+var undefinedFunctionRegex = regexp.MustCompile(`^function "([^"]+)" not defined$`)
+
+// Diagnostic describes a problem with the template in a single field of a message.
+type Diagnostic struct {
+	Path        string
+	Line        int
+	Column      int
+	Message     string
+	Suggestions []string
+}
+
+// LintMessage parses every template field of the message without executing it and returns all problems that were found.
+func (c *TemplateContext) LintMessage(m *actions.MessageWithActions) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	lint := func(path string, text string) {
+		if diagnostic := c.Lint(path, text); diagnostic != nil {
+			diagnostics = append(diagnostics, *diagnostic)
+		}
+	}
+
+	lint("content", m.Content)
+	lint("username", m.Username)
+	lint("avatar_url", m.AvatarURL)
+
+	for i, embed := range m.Embeds {
+		path := fmt.Sprintf("embeds[%d]", i)
+
+		lint(path+".title", embed.Title)
+		lint(path+".description", embed.Description)
+		lint(path+".url", embed.URL)
+
+		if embed.Author != nil {
+			lint(path+".author.name", embed.Author.Name)
+			lint(path+".author.url", embed.Author.URL)
+			lint(path+".author.icon_url", embed.Author.IconURL)
+		}
+
+		if embed.Footer != nil {
+			lint(path+".footer.text", embed.Footer.Text)
+			lint(path+".footer.icon_url", embed.Footer.IconURL)
+		}
+
+		if embed.Image != nil {
+			lint(path+".image.url", embed.Image.URL)
+		}
+
+		if embed.Thumbnail != nil {
+			lint(path+".thumbnail.url", embed.Thumbnail.URL)
+		}
+
+		for j, field := range embed.Fields {
+			lint(fmt.Sprintf("%s.fields[%d].name", path, j), field.Name)
+			lint(fmt.Sprintf("%s.fields[%d].value", path, j), field.Value)
+		}
+	}
+
+	for i, row := range m.Components {
+		for j, component := range row.Components {
+			path := fmt.Sprintf("components[%d].components[%d]", i, j)
+
+			lint(path+".label", component.Label)
+			lint(path+".url", component.URL)
+			lint(path+".placeholder", component.Placeholder)
+
+			for k, option := range component.Options {
+				lint(fmt.Sprintf("%s.options[%d].label", path, k), option.Label)
+				lint(fmt.Sprintf("%s.options[%d].description", path, k), option.Description)
+			}
+		}
+	}
+
+	actionSetIDs := make([]string, 0, len(m.Actions))
+	for id := range m.Actions {
+		actionSetIDs = append(actionSetIDs, id)
+	}
+	sort.Strings(actionSetIDs)
+
+	for _, id := range actionSetIDs {
+		for i, action := range m.Actions[id].Actions {
+			lint(fmt.Sprintf("actions.%s.actions[%d].text", id, i), action.Text)
+		}
+	}
+
+	return diagnostics
+}
+
+// Lint parses the text without executing it and returns a diagnostic if it isn't a valid template.
+func (c *TemplateContext) Lint(path string, text string) *Diagnostic {
+	if text == "" || !strings.Contains(text, DelimLeft) {
+		return nil
+	}
+
+	_, err := c.Parse(text)
+	if err == nil {
+		return nil
+	}
+
+	diagnostic := &Diagnostic{
+		Path:    path,
+		Message: err.Error(),
+	}
+
+	match := parseErrorRegex.FindStringSubmatch(err.Error())
+	if match == nil {
+		return diagnostic
+	}
+
+	diagnostic.Line, _ = strconv.Atoi(match[1])
+	diagnostic.Column, _ = strconv.Atoi(match[2])
+	diagnostic.Message = match[3]
+
+	// The template parser only reports the line for most errors, so we try to locate the offending name ourselves
+	if diagnostic.Column == 0 {
+		if name := quotedNameRegex.FindStringSubmatch(diagnostic.Message); name != nil {
+			lines := strings.Split(text, "\n")
+			if diagnostic.Line > 0 && diagnostic.Line <= len(lines) {
+				if index := strings.Index(lines[diagnostic.Line-1], name[1]); index != -1 {
+					diagnostic.Column = index + 1
+				}
+			}
+		}
+	}
+
+	if name := undefinedFunctionRegex.FindStringSubmatch(diagnostic.Message); name != nil {
+		diagnostic.Suggestions = c.suggestFuncs(name[1])
+	}
+
+	return diagnostic
+}
+
+// suggestFuncs returns the names of known functions that are most similar to the given name.
+func (c *TemplateContext) suggestFuncs(name string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	maxDistance := max(len(name)/3, 2)
+	lowerName := strings.ToLower(name)
+
+	var candidates []candidate
+	for funcName := range c.funcs {
+		distance := levenshtein(lowerName, strings.ToLower(funcName))
+		if distance <= maxDistance {
+			candidates = append(candidates, candidate{name: funcName, distance: distance})
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	suggestions := make([]string, 0, maxLintSuggestions)
+	for _, candidate := range candidates {
+		if len(suggestions) == maxLintSuggestions {
+			break
+		}
+		suggestions = append(suggestions, candidate.name)
+	}
+
+	return suggestions
+}
+
+func levenshtein(a string, b string) int {
+	ar := []rune(a)
+	br := []rune(b)
+
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(br)]
+}
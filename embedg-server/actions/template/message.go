@@ -0,0 +1,317 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/merlinfuchs/discordgo"
+	"github.com/merlinfuchs/embed-generator/embedg-server/actions"
+)
+
+const MaxMessageEmbeds = 10
+const MaxEmbedFields = 25
+const MaxActionRows = 5
+const MaxActionRowButtons = 5
+
+var errNotRenderingMessage = errors.New("embeds and buttons can only be added while rendering a message")
+
+// messageBuilder collects the embeds and buttons that functions add to the message that is currently being rendered.
+// They are only added to the message after all fields have been rendered so they are never parsed as templates themselves.
+type messageBuilder struct {
+	sync.Mutex
+	active  bool
+	embeds  []*discordgo.MessageEmbed
+	buttons []*actions.ComponentWithActions
+}
+
+func (b *messageBuilder) ProvideFuncs(funcs map[string]interface{}) {
+	funcs["embed"] = createEmbed
+	funcs["embedField"] = createEmbedField
+	funcs["button"] = createButton
+	funcs["addEmbed"] = b.addEmbed
+	funcs["addButton"] = b.addButton
+}
+
+func (b *messageBuilder) ProvideData(data map[string]interface{}) {}
+
+func (b *messageBuilder) begin() {
+	b.Lock()
+	defer b.Unlock()
+
+	b.active = true
+	b.embeds = nil
+	b.buttons = nil
+}
+
+func (b *messageBuilder) end() {
+	b.Lock()
+	defer b.Unlock()
+
+	b.active = false
+}
+
+func (b *messageBuilder) addEmbed(embed *discordgo.MessageEmbed) (string, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if !b.active {
+		return "", errNotRenderingMessage
+	}
+	if embed == nil {
+		return "", errors.New("embed must not be nil")
+	}
+
+	b.embeds = append(b.embeds, embed)
+	return "", nil
+}
+
+func (b *messageBuilder) addButton(button *actions.ComponentWithActions) (string, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if !b.active {
+		return "", errNotRenderingMessage
+	}
+	if button == nil {
+		return "", errors.New("button must not be nil")
+	}
+
+	b.buttons = append(b.buttons, button)
+	return "", nil
+}
+
+// apply adds the collected embeds and buttons to the message.
+func (b *messageBuilder) apply(m *actions.MessageWithActions) error {
+	b.Lock()
+	defer b.Unlock()
+
+	if len(m.Embeds)+len(b.embeds) > MaxMessageEmbeds {
+		return fmt.Errorf("message can't have more than %d embeds", MaxMessageEmbeds)
+	}
+	m.Embeds = append(m.Embeds, b.embeds...)
+
+	for _, button := range b.buttons {
+		if button.ActionSetID != "" {
+			if _, ok := m.Actions[button.ActionSetID]; !ok {
+				return fmt.Errorf("button references unknown action set %s", button.ActionSetID)
+			}
+		}
+
+		var row *actions.ComponentWithActions
+		if len(m.Components) != 0 {
+			last := &m.Components[len(m.Components)-1]
+			if last.Type == discordgo.ActionsRowComponent && len(last.Components) < MaxActionRowButtons && rowHasOnlyButtons(last) {
+				row = last
+			}
+		}
+
+		if row == nil {
+			if !m.ComponentsV2Enabled() && len(m.Components) >= MaxActionRows {
+				return fmt.Errorf("message can't have more than %d rows of buttons", MaxActionRows)
+			}
+
+			m.Components = append(m.Components, actions.ComponentWithActions{
+				Type: discordgo.ActionsRowComponent,
+			})
+			row = &m.Components[len(m.Components)-1]
+		}
+
+		row.Components = append(row.Components, *button)
+	}
+
+	return nil
+}
+
+func rowHasOnlyButtons(row *actions.ComponentWithActions) bool {
+	for _, component := range row.Components {
+		if component.Type != discordgo.ButtonComponent {
+			return false
+		}
+	}
+	return true
+}
+
+// createEmbed creates an embed from key-value pairs like "title" "Hello" "color" 0xff0000.
+func createEmbed(values ...interface{}) (*discordgo.MessageEmbed, error) {
+	dict, err := StringKeyDictionary(values...)
+	if err != nil {
+		return nil, err
+	}
+
+	embed := &discordgo.MessageEmbed{}
+	for key, value := range dict {
+		switch strings.ToLower(key) {
+		case "title":
+			embed.Title = ToString(value)
+		case "description":
+			embed.Description = ToString(value)
+		case "url":
+			embed.URL = ToString(value)
+		case "color":
+			embed.Color = int(ToInt64(value))
+		case "timestamp":
+			switch t := value.(type) {
+			case time.Time:
+				embed.Timestamp = t.UTC().Format(time.RFC3339)
+			default:
+				embed.Timestamp = ToString(value)
+			}
+		case "author":
+			author := &discordgo.MessageEmbedAuthor{}
+			if d, ok := value.(SDict); ok {
+				author.Name = ToString(d["name"])
+				author.URL = ToString(d["url"])
+				author.IconURL = ToString(d["icon_url"])
+			} else {
+				author.Name = ToString(value)
+			}
+			embed.Author = author
+		case "footer":
+			footer := &discordgo.MessageEmbedFooter{}
+			if d, ok := value.(SDict); ok {
+				footer.Text = ToString(d["text"])
+				footer.IconURL = ToString(d["icon_url"])
+			} else {
+				footer.Text = ToString(value)
+			}
+			embed.Footer = footer
+		case "image":
+			embed.Image = &discordgo.MessageEmbedImage{URL: embedURL(value)}
+		case "thumbnail":
+			embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: embedURL(value)}
+		case "fields":
+			fields, err := toEmbedFields(value)
+			if err != nil {
+				return nil, err
+			}
+			embed.Fields = fields
+		default:
+			return nil, fmt.Errorf("unknown embed key %s", key)
+		}
+	}
+
+	return embed, nil
+}
+
+func createEmbedField(name interface{}, value interface{}, inline ...bool) *discordgo.MessageEmbedField {
+	return &discordgo.MessageEmbedField{
+		Name:   ToString(name),
+		Value:  ToString(value),
+		Inline: len(inline) > 0 && inline[0],
+	}
+}
+
+// createButton creates a button from key-value pairs like "label" "Vote" "action" "<action set id>".
+func createButton(values ...interface{}) (*actions.ComponentWithActions, error) {
+	dict, err := StringKeyDictionary(values...)
+	if err != nil {
+		return nil, err
+	}
+
+	button := &actions.ComponentWithActions{
+		Type:  discordgo.ButtonComponent,
+		Style: discordgo.PrimaryButton,
+	}
+
+	for key, value := range dict {
+		switch strings.ToLower(key) {
+		case "label":
+			button.Label = ToString(value)
+		case "url":
+			button.URL = ToString(value)
+		case "action":
+			button.ActionSetID = ToString(value)
+		case "disabled":
+			button.Disabled = value == true
+		case "style":
+			style, err := toButtonStyle(value)
+			if err != nil {
+				return nil, err
+			}
+			button.Style = style
+		default:
+			return nil, fmt.Errorf("unknown button key %s", key)
+		}
+	}
+
+	if button.Label == "" {
+		return nil, errors.New("button must have a label")
+	}
+
+	if button.URL != "" {
+		if button.ActionSetID != "" {
+			return nil, errors.New("button can't have both a url and an action")
+		}
+		button.Style = discordgo.LinkButton
+	} else if button.ActionSetID == "" {
+		return nil, errors.New("button must have either a url or an action")
+	}
+
+	return button, nil
+}
+
+func toButtonStyle(v interface{}) (discordgo.ButtonStyle, error) {
+	if s, ok := v.(string); ok {
+		switch strings.ToLower(s) {
+		case "primary":
+			return discordgo.PrimaryButton, nil
+		case "secondary":
+			return discordgo.SecondaryButton, nil
+		case "success":
+			return discordgo.SuccessButton, nil
+		case "danger":
+			return discordgo.DangerButton, nil
+		}
+	}
+
+	style := discordgo.ButtonStyle(ToInt64(v))
+	if style < discordgo.PrimaryButton || style > discordgo.DangerButton {
+		return 0, fmt.Errorf("invalid button style %v", v)
+	}
+	return style, nil
+}
+
+func toEmbedFields(v interface{}) ([]*discordgo.MessageEmbedField, error) {
+	var items []interface{}
+	switch t := v.(type) {
+	case Slice:
+		items = t
+	case []interface{}:
+		items = t
+	case []*discordgo.MessageEmbedField:
+		items = make([]interface{}, len(t))
+		for i, field := range t {
+			items[i] = field
+		}
+	default:
+		return nil, errors.New("embed fields must be a slice")
+	}
+
+	if len(items) > MaxEmbedFields {
+		return nil, fmt.Errorf("embed can't have more than %d fields", MaxEmbedFields)
+	}
+
+	fields := make([]*discordgo.MessageEmbedField, len(items))
+	for i, item := range items {
+		switch t := item.(type) {
+		case *discordgo.MessageEmbedField:
+			fields[i] = t
+		case SDict:
+			fields[i] = createEmbedField(t["name"], t["value"], t["inline"] == true)
+		default:
+			return nil, fmt.Errorf("invalid embed field at index %d", i)
+		}
+	}
+
+	return fields, nil
+}
+
+func embedURL(v interface{}) string {
+	if d, ok := v.(SDict); ok {
+		return ToString(d["url"])
+	}
+	return ToString(v)
+}
@@ -1,18 +1,28 @@
 package template
 
 import (
-	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/merlinfuchs/discordgo"
+	"github.com/merlinfuchs/embed-generator/embedg-server/bot/rest"
 	"github.com/merlinfuchs/embed-generator/embedg-server/model"
 	"github.com/merlinfuchs/embed-generator/embedg-server/store"
+	"gopkg.in/guregu/null.v4"
 )
 
 const MaxKVValueLength = 16 * 1024
 const MaxKVKeyLength = 256
+const MaxKVTTL = 365 * 24 * time.Hour
+
+// LookupOps is the number of template operations that a single Discord entity lookup counts as.
+const LookupOps = 100
 
 type ContextProvider interface {
 	ProvideFuncs(funcs map[string]interface{})
@@ -85,10 +95,288 @@ func (p *ChannelProvider) ProvideData(data map[string]interface{}) {
 	data["Channel"] = NewChannelData(p.state, p.channelID, p.channel)
 }
 
+// MemberProvider provides the member that caused an event, like joining or boosting the server.
+type MemberProvider struct {
+	state   *discordgo.State
+	guildID string
+	member  *discordgo.Member
+}
+
+func NewMemberProvider(state *discordgo.State, guildID string, member *discordgo.Member) *MemberProvider {
+	return &MemberProvider{
+		state:   state,
+		guildID: guildID,
+		member:  member,
+	}
+}
+
+func (p *MemberProvider) ProvideFuncs(funcs map[string]interface{}) {}
+
+func (p *MemberProvider) ProvideData(data map[string]interface{}) {
+	data["Member"] = NewMemberData(p.state, p.guildID, p.member)
+	data["User"] = NewUserData(p.member.User)
+}
+
+type EntityProvider struct {
+	state   *discordgo.State
+	rest    rest.RestClient
+	guildID string
+	exec    *execution
+}
+
+func NewEntityProvider(state *discordgo.State, rest rest.RestClient, guildID string) *EntityProvider {
+	return &EntityProvider{
+		state:   state,
+		rest:    rest,
+		guildID: guildID,
+	}
+}
+
+func (p *EntityProvider) setExecution(exec *execution) {
+	p.exec = exec
+}
+
+func (p *EntityProvider) ProvideFuncs(funcs map[string]interface{}) {
+	funcs["getMember"] = p.getMember
+	funcs["getRole"] = p.getRole
+	funcs["getChannel"] = p.getChannel
+	funcs["roleMembers"] = p.roleMembers
+	funcs["hasRole"] = p.hasRole
+}
+
+func (p *EntityProvider) ProvideData(data map[string]interface{}) {}
+
+func (p *EntityProvider) countLookup() error {
+	if p.exec == nil {
+		return nil
+	}
+	if err := p.exec.ctx.Err(); err != nil {
+		return err
+	}
+	return p.exec.ops.Add(LookupOps)
+}
+
+func (p *EntityProvider) getMember(id interface{}) (*MemberData, error) {
+	if err := p.countLookup(); err != nil {
+		return nil, err
+	}
+
+	userID := toEntityID(id)
+	if userID == "" {
+		return nil, nil
+	}
+
+	member, err := p.state.Member(p.guildID, userID)
+	if err == nil {
+		return NewMemberData(p.state, p.guildID, member), nil
+	}
+
+	if p.rest == nil {
+		return nil, nil
+	}
+
+	member, err = p.rest.GuildMember(p.exec.context(), p.guildID, userID)
+	if err != nil {
+		if err == rest.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return NewMemberData(p.state, p.guildID, member), nil
+}
+
+func (p *EntityProvider) getRole(id interface{}) (*RoleData, error) {
+	if err := p.countLookup(); err != nil {
+		return nil, err
+	}
+
+	roleID := toEntityID(id)
+	role, err := p.state.Role(p.guildID, roleID)
+	if err != nil {
+		return nil, nil
+	}
+
+	return NewRoleData(p.state, p.guildID, roleID, role), nil
+}
+
+func (p *EntityProvider) getChannel(id interface{}) (*ChannelData, error) {
+	if err := p.countLookup(); err != nil {
+		return nil, err
+	}
+
+	channelID := toEntityID(id)
+	channel, err := p.state.Channel(channelID)
+	if err != nil || channel.GuildID != p.guildID {
+		return nil, nil
+	}
+
+	return NewChannelData(p.state, channelID, channel), nil
+}
+
+// roleMembers returns the number of cached members that have the role.
+func (p *EntityProvider) roleMembers(id interface{}) (int, error) {
+	if err := p.countLookup(); err != nil {
+		return 0, err
+	}
+
+	roleID := toEntityID(id)
+	guild, err := p.state.Guild(p.guildID)
+	if err != nil {
+		return 0, nil
+	}
+
+	p.state.RLock()
+	defer p.state.RUnlock()
+
+	count := 0
+	for _, member := range guild.Members {
+		for _, memberRoleID := range member.Roles {
+			if memberRoleID == roleID {
+				count++
+				break
+			}
+		}
+	}
+
+	return count, nil
+}
+
+func (p *EntityProvider) hasRole(member interface{}, role interface{}) (bool, error) {
+	memberData, ok := member.(*MemberData)
+	if !ok {
+		var err error
+		memberData, err = p.getMember(member)
+		if err != nil || memberData == nil {
+			return false, err
+		}
+	}
+
+	roleID := toEntityID(role)
+	for _, memberRoleID := range memberData.m.Roles {
+		if memberRoleID == roleID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// TranslationProvider provides the t function which looks up guild translations for the given locales.
+// Locales are tried in order, each one followed by its base language, before the key itself is returned.
+type TranslationProvider struct {
+	sync.Mutex
+	guildID          string
+	translationStore store.TranslationStore
+	locales          []string
+	exec             *execution
+
+	loaded       bool
+	translations map[string]map[string]string
+}
+
+func NewTranslationProvider(guildID string, translationStore store.TranslationStore, locales ...string) *TranslationProvider {
+	return &TranslationProvider{
+		guildID:          guildID,
+		translationStore: translationStore,
+		locales:          LocaleFallbacks(locales...),
+	}
+}
+
+func (p *TranslationProvider) setExecution(exec *execution) {
+	p.exec = exec
+}
+
+func (p *TranslationProvider) ProvideFuncs(funcs map[string]interface{}) {
+	funcs["t"] = p.translate
+}
+
+func (p *TranslationProvider) ProvideData(data map[string]interface{}) {}
+
+func (p *TranslationProvider) translate(key string, args ...interface{}) (string, error) {
+	if err := p.load(); err != nil {
+		return "", err
+	}
+
+	value := key
+	for _, locale := range p.locales {
+		if v, ok := p.translations[locale][key]; ok {
+			value = v
+			break
+		}
+	}
+
+	if len(args) == 0 {
+		return value, nil
+	}
+	return fmt.Sprintf(value, args...), nil
+}
+
+// load fetches the translations for all locales the first time a translation is requested.
+func (p *TranslationProvider) load() error {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.loaded {
+		return nil
+	}
+
+	p.translations = make(map[string]map[string]string, len(p.locales))
+	if len(p.locales) != 0 {
+		translations, err := p.translationStore.GetTranslationsForLocales(p.exec.context(), p.guildID, p.locales)
+		if err != nil {
+			return fmt.Errorf("failed to load translations: %w", err)
+		}
+
+		for _, translation := range translations {
+			if p.translations[translation.Locale] == nil {
+				p.translations[translation.Locale] = make(map[string]string)
+			}
+			p.translations[translation.Locale][translation.Key] = translation.Value
+		}
+	}
+
+	p.loaded = true
+	return nil
+}
+
+// LocaleFallbacks returns the locales in order of preference with each one followed by its base language.
+// Empty and duplicate locales are removed.
+func LocaleFallbacks(locales ...string) []string {
+	res := make([]string, 0, len(locales)*2)
+	seen := make(map[string]bool, len(locales)*2)
+
+	add := func(locale string) {
+		if locale != "" && !seen[locale] {
+			seen[locale] = true
+			res = append(res, locale)
+		}
+	}
+
+	for _, locale := range locales {
+		add(locale)
+		if base, _, ok := strings.Cut(locale, "-"); ok {
+			add(base)
+		}
+	}
+
+	return res
+}
+
+// GuildLocale returns the preferred locale of the guild or an empty string if the guild isn't available.
+func GuildLocale(state *discordgo.State, guildID string) string {
+	guild, err := state.Guild(guildID)
+	if err != nil {
+		return ""
+	}
+	return guild.PreferredLocale
+}
+
 type KVProvider struct {
 	guildID      string
 	kvStore      store.KVEntryStore
 	maxGuildKeys int
+	exec         *execution
 }
 
 func NewKVProvider(guildID string, kvStore store.KVEntryStore, maxGuildKeys int) *KVProvider {
@@ -99,18 +387,33 @@ func NewKVProvider(guildID string, kvStore store.KVEntryStore, maxGuildKeys int)
 	}
 }
 
+func (p *KVProvider) setExecution(exec *execution) {
+	p.exec = exec
+}
+
 func (p *KVProvider) ProvideFuncs(funcs map[string]interface{}) {
 	funcs["kvSet"] = p.setKey
+	funcs["kvSetEx"] = p.setKeyWithTTL
+	funcs["kvExpire"] = p.expireKey
+	funcs["kvPersist"] = p.persistKey
+	funcs["kvTTL"] = p.keyTTL
 	funcs["kvGet"] = p.getKey
 	funcs["kvIncrease"] = p.increaseKey
 	funcs["kvDelete"] = p.deleteKey
 	funcs["kvSearch"] = p.searchKeys
+	funcs["kvSetJSON"] = p.setKeyJSON
+	funcs["kvGetJSON"] = p.getKeyJSON
+	funcs["kvListPush"] = p.pushListItems
+	funcs["kvListRemove"] = p.removeListItem
+	funcs["kvListContains"] = p.listContains
+	funcs["kvSetAdd"] = p.addSetItem
+	funcs["kvMapSet"] = p.setMapField
 }
 
 func (p *KVProvider) ProvideData(data map[string]interface{}) {}
 
 func (kv *KVProvider) getKey(key string) (string, error) {
-	entry, err := kv.kvStore.GetKVEntry(context.TODO(), kv.guildID, key)
+	entry, err := kv.kvStore.GetKVEntry(kv.exec.context(), kv.guildID, key)
 	if err != nil {
 		if err == store.ErrNotFound {
 			return "", nil
@@ -121,6 +424,19 @@ func (kv *KVProvider) getKey(key string) (string, error) {
 }
 
 func (kv *KVProvider) setKey(key string, value string) error {
+	return kv.setKeyWithExpiry(key, value, null.Time{})
+}
+
+func (kv *KVProvider) setKeyWithTTL(key string, value string, ttl interface{}) error {
+	duration, err := toTTL(ttl)
+	if err != nil {
+		return err
+	}
+
+	return kv.setKeyWithExpiry(key, value, null.TimeFrom(time.Now().UTC().Add(duration)))
+}
+
+func (kv *KVProvider) setKeyWithExpiry(key string, value string, expiresAt null.Time) error {
 	if len(key) > MaxKVKeyLength {
 		return fmt.Errorf("key exceeds maximum length of %d", MaxKVKeyLength)
 	}
@@ -132,10 +448,11 @@ func (kv *KVProvider) setKey(key string, value string) error {
 		return err
 	}
 
-	err := kv.kvStore.SetKVEntry(context.TODO(), model.KVEntry{
+	err := kv.kvStore.SetKVEntry(kv.exec.context(), model.KVEntry{
 		GuildID:   kv.guildID,
 		Key:       key,
 		Value:     value,
+		ExpiresAt: expiresAt,
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
 	})
@@ -145,6 +462,51 @@ func (kv *KVProvider) setKey(key string, value string) error {
 	return nil
 }
 
+func (kv *KVProvider) expireKey(key string, ttl interface{}) (bool, error) {
+	duration, err := toTTL(ttl)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = kv.kvStore.SetKVEntryExpiresAt(kv.exec.context(), kv.guildID, key, null.TimeFrom(time.Now().UTC().Add(duration)))
+	if err != nil {
+		if err == store.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (kv *KVProvider) persistKey(key string) (bool, error) {
+	_, err := kv.kvStore.SetKVEntryExpiresAt(kv.exec.context(), kv.guildID, key, null.Time{})
+	if err != nil {
+		if err == store.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// keyTTL returns the remaining lifetime of the key in seconds, -1 if the key has no expiry and -2 if it doesn't exist.
+func (kv *KVProvider) keyTTL(key string) (int, error) {
+	entry, err := kv.kvStore.GetKVEntry(kv.exec.context(), kv.guildID, key)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return -2, nil
+		}
+		return 0, err
+	}
+
+	if !entry.ExpiresAt.Valid {
+		return -1, nil
+	}
+
+	remaining := entry.ExpiresAt.Time.Sub(time.Now().UTC())
+	return int(math.Max(math.Ceil(remaining.Seconds()), 0)), nil
+}
+
 func (kv *KVProvider) increaseKey(key string, delta int) (string, error) {
 	if len(key) > MaxKVKeyLength {
 		return "", fmt.Errorf("key exceeds maximum length of %d", MaxKVKeyLength)
@@ -154,7 +516,7 @@ func (kv *KVProvider) increaseKey(key string, delta int) (string, error) {
 		return "", err
 	}
 
-	entry, err := kv.kvStore.IncreaseKVEntry(context.TODO(), model.KVEntryIncreaseParams{
+	entry, err := kv.kvStore.IncreaseKVEntry(kv.exec.context(), model.KVEntryIncreaseParams{
 		GuildID:   kv.guildID,
 		Key:       key,
 		Delta:     delta,
@@ -171,9 +533,9 @@ func (kv *KVProvider) increaseKey(key string, delta int) (string, error) {
 }
 
 func (kv *KVProvider) deleteKey(key string) (string, error) {
-	entry, err := kv.kvStore.DeleteKVEntry(context.TODO(), kv.guildID, key)
+	entry, err := kv.kvStore.DeleteKVEntry(kv.exec.context(), kv.guildID, key)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if err == store.ErrNotFound {
 			return "", nil
 		}
 		return "", err
@@ -182,7 +544,7 @@ func (kv *KVProvider) deleteKey(key string) (string, error) {
 }
 
 func (kv *KVProvider) searchKeys(pattern string) (map[string]string, error) {
-	entries, err := kv.kvStore.SearchKVEntries(context.TODO(), kv.guildID, pattern)
+	entries, err := kv.kvStore.SearchKVEntries(kv.exec.context(), kv.guildID, pattern)
 	if err != nil {
 		return nil, err
 	}
@@ -195,8 +557,156 @@ func (kv *KVProvider) searchKeys(pattern string) (map[string]string, error) {
 	return result, nil
 }
 
+func (kv *KVProvider) setKeyJSON(key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode value as JSON: %w", err)
+	}
+
+	return kv.setKeyWithExpiry(key, string(raw), null.Time{})
+}
+
+// getKeyJSON returns the decoded JSON value of the key with objects as sdicts and arrays as slices.
+func (kv *KVProvider) getKeyJSON(key string) (interface{}, error) {
+	entry, err := kv.kvStore.GetKVEntry(kv.exec.context(), kv.guildID, key)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(entry.Value), &value); err != nil {
+		return nil, fmt.Errorf("value of key %s is not valid JSON", key)
+	}
+
+	return fromJSONValue(value), nil
+}
+
+// pushListItems appends the items to the list and returns the new length of the list.
+func (kv *KVProvider) pushListItems(key string, items ...interface{}) (int, error) {
+	if len(items) == 0 {
+		return 0, fmt.Errorf("at least one item is required")
+	}
+
+	raw, err := kv.encodeOperand(key, items)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := kv.checkKeyCountLimit(); err != nil {
+		return 0, err
+	}
+
+	entry, err := kv.kvStore.PushKVEntryList(kv.exec.context(), model.KVEntryCollectionParams{
+		GuildID:   kv.guildID,
+		Key:       key,
+		Value:     raw,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return 0, kvOperationError(key, "list", err)
+	}
+
+	return jsonListLength(entry.Value), nil
+}
+
+// removeListItem removes all occurrences of the item from the list and returns the new length of the list.
+func (kv *KVProvider) removeListItem(key string, item interface{}) (int, error) {
+	raw, err := kv.encodeOperand(key, item)
+	if err != nil {
+		return 0, err
+	}
+
+	entry, err := kv.kvStore.RemoveKVEntryListItem(kv.exec.context(), kv.guildID, key, raw)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return 0, nil
+		}
+		return 0, kvOperationError(key, "list", err)
+	}
+
+	return jsonListLength(entry.Value), nil
+}
+
+func (kv *KVProvider) listContains(key string, item interface{}) (bool, error) {
+	raw, err := kv.encodeOperand(key, item)
+	if err != nil {
+		return false, err
+	}
+
+	return kv.kvStore.KVEntryListContains(kv.exec.context(), kv.guildID, key, raw)
+}
+
+// addSetItem adds the item to the list if it isn't already present and returns whether it was added.
+func (kv *KVProvider) addSetItem(key string, item interface{}) (bool, error) {
+	raw, err := kv.encodeOperand(key, []interface{}{item})
+	if err != nil {
+		return false, err
+	}
+
+	if err := kv.checkKeyCountLimit(); err != nil {
+		return false, err
+	}
+
+	_, added, err := kv.kvStore.AddKVEntrySetItem(kv.exec.context(), model.KVEntryCollectionParams{
+		GuildID:   kv.guildID,
+		Key:       key,
+		Value:     raw,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return false, kvOperationError(key, "list", err)
+	}
+
+	return added, nil
+}
+
+func (kv *KVProvider) setMapField(key string, field string, value interface{}) error {
+	raw, err := kv.encodeOperand(key, map[string]interface{}{field: value})
+	if err != nil {
+		return err
+	}
+
+	if err := kv.checkKeyCountLimit(); err != nil {
+		return err
+	}
+
+	_, err = kv.kvStore.SetKVEntryMapField(kv.exec.context(), model.KVEntryCollectionParams{
+		GuildID:   kv.guildID,
+		Key:       key,
+		Value:     raw,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return kvOperationError(key, "map", err)
+	}
+
+	return nil
+}
+
+func (kv *KVProvider) encodeOperand(key string, v interface{}) (json.RawMessage, error) {
+	if len(key) > MaxKVKeyLength {
+		return nil, fmt.Errorf("key exceeds maximum length of %d", MaxKVKeyLength)
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value as JSON: %w", err)
+	}
+	if len(raw) > MaxKVValueLength {
+		return nil, fmt.Errorf("value exceeds maximum length of %d", MaxKVValueLength)
+	}
+
+	return raw, nil
+}
+
 func (kv *KVProvider) checkKeyCountLimit() error {
-	entryCount, err := kv.kvStore.CountKVEntries(context.TODO(), kv.guildID)
+	entryCount, err := kv.kvStore.CountKVEntries(kv.exec.context(), kv.guildID)
 	if err != nil {
 		return fmt.Errorf("failed to count KV keys: %w", err)
 	}
@@ -207,3 +717,88 @@ func (kv *KVProvider) checkKeyCountLimit() error {
 
 	return nil
 }
+
+// toTTL accepts a duration, a duration string like "1h30m" or a number of seconds.
+func toTTL(v interface{}) (time.Duration, error) {
+	ttl, err := toDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid TTL: %w", err)
+	}
+
+	if ttl <= 0 {
+		return 0, fmt.Errorf("TTL must be positive")
+	}
+	if ttl > MaxKVTTL {
+		return 0, fmt.Errorf("TTL exceeds maximum of %s", MaxKVTTL)
+	}
+	return ttl, nil
+}
+
+// toDuration accepts a duration, a duration string like "1h30m" or a number of seconds.
+func toDuration(v interface{}) (time.Duration, error) {
+	switch t := v.(type) {
+	case time.Duration:
+		return t, nil
+	case string:
+		parsed, err := time.ParseDuration(t)
+		if err != nil {
+			seconds, serr := strconv.ParseFloat(t, 64)
+			if serr != nil {
+				return 0, err
+			}
+			parsed = time.Duration(seconds * float64(time.Second))
+		}
+		return parsed, nil
+	default:
+		return time.Duration(ToFloat64(v) * float64(time.Second)), nil
+	}
+}
+
+func kvOperationError(key string, kind string, err error) error {
+	switch err {
+	case store.ErrWrongValueType:
+		return fmt.Errorf("value of key %s is not a %s", key, kind)
+	case store.ErrValueTooLarge:
+		return fmt.Errorf("value exceeds maximum length of %d", MaxKVValueLength)
+	}
+	return err
+}
+
+func jsonListLength(value string) int {
+	var list []json.RawMessage
+	if err := json.Unmarshal([]byte(value), &list); err != nil {
+		return 0
+	}
+	return len(list)
+}
+
+// fromJSONValue converts decoded JSON objects and arrays into the types used by the template functions.
+func fromJSONValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		res := make(SDict, len(t))
+		for k, v := range t {
+			res[k] = fromJSONValue(v)
+		}
+		return res
+	case []interface{}:
+		res := make(Slice, len(t))
+		for i, v := range t {
+			res[i] = fromJSONValue(v)
+		}
+		return res
+	}
+	return v
+}
+
+// toEntityID accepts an ID, a mention or any of the data wrappers and returns the ID.
+func toEntityID(v interface{}) string {
+	switch e := v.(type) {
+	case interface{ ID() string }:
+		return e.ID()
+	case UserData:
+		return e.ID()
+	}
+
+	return strings.Trim(ToString(v), "<@!&#>")
+}
@@ -0,0 +1,78 @@
+package template
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/merlinfuchs/discordgo"
+)
+
+func TestInteractionUserEntity(t *testing.T) {
+	state := discordgo.NewState()
+	i := &discordgo.Interaction{
+		GuildID: "1",
+		Member: &discordgo.Member{
+			User:  &discordgo.User{ID: "2"},
+			Roles: []string{"3"},
+		},
+	}
+
+	user := NewInteractionData(state, i).User()
+	if _, ok := user.(*MemberData); !ok {
+		t.Fatalf("expected user of guild interaction to be *MemberData, got %T", user)
+	}
+
+	if id := toEntityID(user); id != "2" {
+		t.Fatalf("expected entity ID 2, got %q", id)
+	}
+
+	p := NewEntityProvider(state, nil, "1")
+	for role, want := range map[string]bool{"3": true, "4": false} {
+		got, err := p.hasRole(user, role)
+		if err != nil {
+			t.Fatalf("hasRole(%s) failed: %v", role, err)
+		}
+		if got != want {
+			t.Errorf("hasRole(%s) = %v, want %v", role, got, want)
+		}
+	}
+}
+
+// testLookupProvider provides a function that counts as a lookup without doing anything.
+type testLookupProvider struct {
+	exec *execution
+}
+
+func (p *testLookupProvider) setExecution(exec *execution) {
+	p.exec = exec
+}
+
+func (p *testLookupProvider) ProvideFuncs(funcs map[string]interface{}) {
+	funcs["lookup"] = func() (string, error) {
+		return "", p.exec.ops.Add(LookupOps)
+	}
+}
+
+func (p *testLookupProvider) ProvideData(data map[string]interface{}) {}
+
+func TestExecuteOpsBudget(t *testing.T) {
+	maxOps := 10 * LookupOps
+	functionOps := maxOps / FunctionOpsShare
+
+	c := NewContext(context.Background(), "TEST", maxOps, &testLookupProvider{})
+	if _, err := c.ParseAndExecute(strings.Repeat("{{lookup}}", functionOps/LookupOps)); err != nil {
+		t.Fatalf("lookups within the budget failed: %v", err)
+	}
+
+	// The share of the engine isn't available to functions, so the total can't exceed the maximum
+	c = NewContext(context.Background(), "TEST", maxOps, &testLookupProvider{})
+	if _, err := c.ParseAndExecute(strings.Repeat("{{lookup}}", functionOps/LookupOps+1)); err == nil {
+		t.Fatal("expected lookups beyond the share of functions to fail")
+	}
+
+	// The reservation of the engine is released after the execution
+	if used := c.exec.ops.Used(); used > functionOps+LookupOps {
+		t.Fatalf("expected only function operations to be used after the execution, got %d", used)
+	}
+}
@@ -0,0 +1,132 @@
+package template
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/store"
+)
+
+// MaxIncludeDepth is the maximum number of snippets that can include each other.
+const MaxIncludeDepth = 5
+
+// IncludeOps is the number of template operations that each included snippet can use.
+// The template engine doesn't report how many operations it has performed for a snippet,
+// so the full budget of the snippet is deducted from the budget of the context up front.
+const IncludeOps = 500
+
+// SnippetProvider provides the include function which executes the template snippets of the guild.
+type SnippetProvider struct {
+	sync.Mutex
+	guildID      string
+	snippetStore store.TemplateSnippetStore
+	exec         *execution
+	tmpl         *TemplateContext
+
+	snippets map[string]string
+	stack    []string
+}
+
+func NewSnippetProvider(guildID string, snippetStore store.TemplateSnippetStore) *SnippetProvider {
+	return &SnippetProvider{
+		guildID:      guildID,
+		snippetStore: snippetStore,
+		snippets:     make(map[string]string),
+	}
+}
+
+func (p *SnippetProvider) setExecution(exec *execution) {
+	p.exec = exec
+}
+
+func (p *SnippetProvider) setTemplateContext(c *TemplateContext) {
+	p.tmpl = c
+}
+
+func (p *SnippetProvider) ProvideFuncs(funcs map[string]interface{}) {
+	funcs["include"] = p.include
+}
+
+func (p *SnippetProvider) ProvideData(data map[string]interface{}) {}
+
+// include executes the snippet with the given data or the data of the context if none is given.
+func (p *SnippetProvider) include(name string, data ...interface{}) (string, error) {
+	if p.tmpl == nil {
+		return "", fmt.Errorf("include: snippets are not available here")
+	}
+
+	if err := p.push(name); err != nil {
+		return "", err
+	}
+	defer p.pop()
+
+	if err := p.exec.ops.Add(IncludeOps); err != nil {
+		return "", err
+	}
+
+	content, err := p.load(name)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := p.tmpl.Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("include %s: %w", name, err)
+	}
+
+	var snippetData interface{} = p.tmpl.data
+	if len(data) > 0 {
+		snippetData = data[0]
+	}
+
+	res, err := p.tmpl.executeWithOps(tmpl, snippetData, IncludeOps)
+	if err != nil {
+		return "", fmt.Errorf("include %s: %w", name, err)
+	}
+	return res, nil
+}
+
+func (p *SnippetProvider) push(name string) error {
+	p.Lock()
+	defer p.Unlock()
+
+	if slices.Contains(p.stack, name) {
+		return fmt.Errorf("include: snippet %s includes itself: %s", name, strings.Join(append(p.stack, name), " -> "))
+	}
+	if len(p.stack) >= MaxIncludeDepth {
+		return fmt.Errorf("include: snippets can't be nested more than %d levels deep", MaxIncludeDepth)
+	}
+
+	p.stack = append(p.stack, name)
+	return nil
+}
+
+func (p *SnippetProvider) pop() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.stack = p.stack[:len(p.stack)-1]
+}
+
+// load fetches the content of the snippet once per context.
+func (p *SnippetProvider) load(name string) (string, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if content, ok := p.snippets[name]; ok {
+		return content, nil
+	}
+
+	snippet, err := p.snippetStore.GetTemplateSnippet(p.exec.context(), p.guildID, name)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return "", fmt.Errorf("include: unknown snippet %s", name)
+		}
+		return "", fmt.Errorf("include: failed to load snippet %s: %w", name, err)
+	}
+
+	p.snippets[name] = snippet.Content
+	return snippet.Content, nil
+}
@@ -1,6 +1,9 @@
 package template
 
-import "io"
+import (
+	"context"
+	"io"
+)
 
 type limitedWriter struct {
 	W io.Writer
@@ -29,3 +32,20 @@ func (l *limitedWriter) Write(p []byte) (n int, err error) {
 func LimitWriter(w io.Writer, n int64) io.Writer {
 	return &limitedWriter{W: w, N: n}
 }
+
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (c *contextWriter) Write(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.w.Write(p)
+}
+
+// ContextWriter returns a Writer that fails all writes once the context is done.
+func ContextWriter(ctx context.Context, w io.Writer) io.Writer {
+	return &contextWriter{ctx: ctx, w: w}
+}
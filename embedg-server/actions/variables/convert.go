@@ -0,0 +1,287 @@
+package variables
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Conversion describes the changes that were made when converting variables to templates.
+type Conversion struct {
+	Converted   int
+	Unsupported []string
+}
+
+func (c *Conversion) add(other Conversion) {
+	c.Converted += other.Converted
+	for _, variable := range other.Unsupported {
+		if !slices.Contains(c.Unsupported, variable) {
+			c.Unsupported = append(c.Unsupported, variable)
+		}
+	}
+}
+
+// Changed returns whether any variable was converted.
+func (c Conversion) Changed() bool {
+	return c.Converted > 0
+}
+
+var userFields = map[string]string{
+	"id":            "ID",
+	"name":          "Name",
+	"username":      "Username",
+	"discriminator": "Discriminator",
+	"avatar":        "Avatar",
+	"avatar_url":    "AvatarURL",
+	"banner":        "Banner",
+	"banner_url":    "BannerURL",
+	"global_name":   "GlobalName",
+	"mention":       "Mention",
+}
+
+var guildFields = map[string]string{
+	"id":           "ID",
+	"name":         "Name",
+	"description":  "Description",
+	"icon":         "Icon",
+	"icon_url":     "IconURL",
+	"banner":       "Banner",
+	"banner_url":   "BannerURL",
+	"member_count": "MemberCount",
+	"boost_count":  "BoostCount",
+	"boost_level":  "BoostLevel",
+}
+
+var channelFields = map[string]string{
+	"id":      "ID",
+	"name":    "Name",
+	"topic":   "Topic",
+	"mention": "Mention",
+}
+
+var commandFields = map[string]string{
+	"id":   "ID",
+	"name": "Name",
+}
+
+// optionFields contains the fields of all option types because the type of an option isn't known without the command.
+var optionFields = map[string]string{
+	"id":            "ID",
+	"name":          "Name",
+	"username":      "Username",
+	"discriminator": "Discriminator",
+	"avatar":        "Avatar",
+	"avatar_url":    "AvatarURL",
+	"banner":        "Banner",
+	"banner_url":    "BannerURL",
+	"global_name":   "GlobalName",
+	"mention":       "Mention",
+	"topic":         "Topic",
+	"url":           "URL",
+}
+
+// convertVariable returns the template for the variable or false if there is no equivalent.
+// The lookup mirrors the providers that HandleActionInteraction uses to fill variables.
+// Saved messages are also sent without an interaction, in that case the variables of the interaction output
+// themselves unchanged like they did before instead of failing the whole message.
+func convertVariable(variable string) (string, bool) {
+	keys := strings.Split(variable, ".")
+
+	field := func(base string, fields map[string]string, keys []string) (string, bool) {
+		switch len(keys) {
+		case 0:
+			return base, true
+		case 1:
+			if name, ok := fields[keys[0]]; ok {
+				return base + "." + name, true
+			}
+		}
+		return "", false
+	}
+
+	// The literal is quoted inside an action so that converting the template again doesn't pick it up
+	literal := fmt.Sprintf("{{%q}}", "{"+variable+"}")
+	withInteraction := func(expr string, ok bool) (string, bool) {
+		if !ok {
+			return "", false
+		}
+		return "{{with .Interaction}}{{" + expr + "}}{{else}}" + literal + "{{end}}", true
+	}
+	withCommand := func(expr string, ok bool) (string, bool) {
+		if !ok {
+			return "", false
+		}
+		return "{{with .Interaction}}{{with .Command}}{{" + expr + "}}{{else}}" + literal + "{{end}}{{else}}" + literal + "{{end}}", true
+	}
+
+	switch keys[0] {
+	case "user":
+		return withInteraction(field(".User", userFields, keys[1:]))
+	case "guild", "server":
+		expr, ok := field(".Guild", guildFields, keys[1:])
+		return "{{" + expr + "}}", ok
+	case "channel":
+		expr, ok := field(".Channel", channelFields, keys[1:])
+		return "{{" + expr + "}}", ok
+	case "cmd":
+		if len(keys) >= 3 && keys[1] == "args" {
+			option := fmt.Sprintf("index .Args %q", keys[2])
+			if len(keys) == 3 {
+				return withCommand(option, true)
+			}
+			return withCommand(field("("+option+")", optionFields, keys[3:]))
+		}
+		if len(keys) == 1 {
+			return withCommand(".", true)
+		}
+		return withCommand(field("", commandFields, keys[1:]))
+	}
+
+	return "", false
+}
+
+// templateActionRegex matches the actions of templates, they are skipped so that converting a string twice doesn't
+// report the inner braces of an already converted variable as unsupported.
+var templateActionRegex = regexp.MustCompile(`(?s)\{\{.*?\}\}`)
+
+// ConvertString rewrites the legacy variables in the string to the equivalent template syntax.
+// Variables without an equivalent are left unchanged and reported as unsupported.
+func ConvertString(s string) (string, Conversion) {
+	var conversion Conversion
+	var res strings.Builder
+
+	last := 0
+	for _, loc := range templateActionRegex.FindAllStringIndex(s, -1) {
+		res.WriteString(convertText(s[last:loc[0]], &conversion))
+		res.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	res.WriteString(convertText(s[last:], &conversion))
+
+	return res.String(), conversion
+}
+
+// convertText converts the legacy variables in text that isn't part of a template action.
+func convertText(s string, conversion *Conversion) string {
+	return variableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		key := match[1 : len(match)-1]
+
+		res, ok := convertVariable(key)
+		if !ok {
+			conversion.add(Conversion{Unsupported: []string{key}})
+			return match
+		}
+
+		conversion.Converted++
+		return res
+	})
+}
+
+// ConvertMessageJSON converts the variables in all fields of the message that FillMessage fills, as well as in the
+// texts of its action sets. The message is converted on the raw JSON so that fields of the editor are preserved.
+func ConvertMessageJSON(raw json.RawMessage) (json.RawMessage, Conversion, error) {
+	var message map[string]interface{}
+	if err := decodeJSON(raw, &message); err != nil {
+		return nil, Conversion{}, err
+	}
+
+	var conversion Conversion
+	convertFields(message, &conversion, "content", "username", "avatar_url")
+
+	for _, embed := range objects(message["embeds"]) {
+		convertFields(embed, &conversion, "title", "description", "url")
+
+		if author, ok := embed["author"].(map[string]interface{}); ok {
+			convertFields(author, &conversion, "name", "url", "icon_url")
+		}
+		if footer, ok := embed["footer"].(map[string]interface{}); ok {
+			convertFields(footer, &conversion, "text", "icon_url")
+		}
+		if image, ok := embed["image"].(map[string]interface{}); ok {
+			convertFields(image, &conversion, "url")
+		}
+		if thumbnail, ok := embed["thumbnail"].(map[string]interface{}); ok {
+			convertFields(thumbnail, &conversion, "url")
+		}
+		for _, field := range objects(embed["fields"]) {
+			convertFields(field, &conversion, "name", "value")
+		}
+	}
+
+	if actionSets, ok := message["actions"].(map[string]interface{}); ok {
+		for _, actionSet := range actionSets {
+			if actionSet, ok := actionSet.(map[string]interface{}); ok {
+				convertActionSet(actionSet, &conversion)
+			}
+		}
+	}
+
+	if !conversion.Changed() {
+		return raw, conversion, nil
+	}
+
+	res, err := json.Marshal(message)
+	return res, conversion, err
+}
+
+// ConvertActionSetJSON converts the variables in the texts of the actions.
+func ConvertActionSetJSON(raw json.RawMessage) (json.RawMessage, Conversion, error) {
+	var actionSet map[string]interface{}
+	if err := decodeJSON(raw, &actionSet); err != nil {
+		return nil, Conversion{}, err
+	}
+
+	var conversion Conversion
+	convertActionSet(actionSet, &conversion)
+
+	if !conversion.Changed() {
+		return raw, conversion, nil
+	}
+
+	res, err := json.Marshal(actionSet)
+	return res, conversion, err
+}
+
+func convertActionSet(actionSet map[string]interface{}, conversion *Conversion) {
+	for _, action := range objects(actionSet["actions"]) {
+		convertFields(action, conversion, "text")
+	}
+}
+
+func convertFields(obj map[string]interface{}, conversion *Conversion, keys ...string) {
+	for _, key := range keys {
+		value, ok := obj[key].(string)
+		if !ok {
+			continue
+		}
+
+		res, c := ConvertString(value)
+		obj[key] = res
+		conversion.add(c)
+	}
+}
+
+// decodeJSON keeps numbers as they are so that encoding the value again doesn't change them.
+func decodeJSON(raw json.RawMessage, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func objects(v interface{}) []map[string]interface{} {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	res := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(map[string]interface{}); ok {
+			res = append(res, obj)
+		}
+	}
+	return res
+}
@@ -3,6 +3,10 @@ package api
 import (
 	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/recover"
@@ -38,8 +42,22 @@ func Serve(stores *Stores) {
 
 	registerRoutes(app, stores, stores.Bot, managers)
 
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+
+		log.Info().Msg("Shutting down server")
+		if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
+			log.Error().Err(err).Msg("Failed to shut down server")
+		}
+	}()
+
 	err := app.Listen(fmt.Sprintf("%s:%d", viper.GetString("api.host"), viper.GetInt("api.port")))
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to start server")
 	}
+
+	// Custom bots are released after the server stopped accepting requests so other instances can take them over
+	managers.customBots.Close()
 }
@@ -0,0 +1,303 @@
+package custom_bots
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/merlinfuchs/discordgo"
+	"github.com/merlinfuchs/embed-generator/embedg-server/actions"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/helpers"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/session"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/wire"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/merlinfuchs/embed-generator/embedg-server/util"
+	"github.com/sqlc-dev/pqtype"
+)
+
+func (h *CustomBotsHandler) HandleExportCustomCommands(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	commands, err := h.pg.Q.GetCustomCommands(c.Context(), guildID)
+	if err != nil {
+		return err
+	}
+
+	// Sort the commands to make bundles of the same commands identical
+	slices.SortFunc(commands, func(a, b pgmodel.CustomCommand) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.Name, b.Name))
+	})
+
+	bundle := wire.CustomCommandBundleWire{
+		Version:    wire.CustomCommandBundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Commands:   make([]wire.CustomCommandBundleEntryWire, 0, len(commands)),
+	}
+	for _, cmd := range commands {
+		w, err := customCommandToWire(cmd)
+		if err != nil {
+			return err
+		}
+
+		bundle.Commands = append(bundle.Commands, wire.CustomCommandBundleEntryWire{
+			Type:                     w.Type,
+			Name:                     w.Name,
+			Description:              w.Description,
+			NameLocalizations:        w.NameLocalizations,
+			DescriptionLocalizations: w.DescriptionLocalizations,
+			DefaultMemberPermissions: w.DefaultMemberPermissions,
+			Contexts:                 w.Contexts,
+			Enabled:                  w.Enabled,
+			Parameters:               w.Parameters,
+			Actions:                  w.Actions,
+		})
+	}
+
+	return c.JSON(wire.CustomCommandsExportResponseWire{
+		Success: true,
+		Data:    bundle,
+	})
+}
+
+func (h *CustomBotsHandler) HandleImportCustomCommands(c *fiber.Ctx, req wire.CustomCommandsImportRequestWire) error {
+	session := c.Locals("session").(*session.Session)
+	req.Normalize()
+
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	features, err := h.planStore.GetPlanFeaturesForGuild(c.Context(), guildID)
+	if err != nil {
+		return err
+	}
+
+	if !features.CustomBot {
+		return helpers.Forbidden("insufficient_plan", "This feature is not available on your plan!")
+	}
+
+	existing, err := h.pg.Q.GetCustomCommands(c.Context(), guildID)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve custom commands: %w", err)
+	}
+
+	derivedPerms, err := h.actionParser.DerivePermissionsForActions(session.UserID, guildID, "")
+	if err != nil {
+		return helpers.BadRequest("invalid_actions", err.Error())
+	}
+
+	rawDerivedPerms, err := json.Marshal(derivedPerms)
+	if err != nil {
+		return err
+	}
+
+	// merged is the set of commands the guild will have after the import
+	merged := slices.Clone(existing)
+
+	var inserts []pgmodel.InsertCustomCommandParams
+	var updates []pgmodel.UpdateCustomCommandParams
+	skipped := make([]string, 0)
+	missingRefs := make([]wire.CustomCommandImportMissingReferenceWire, 0)
+
+	for _, entry := range req.Bundle.Commands {
+		actionSet := actions.ActionSet{}
+		if err := json.Unmarshal(entry.Actions, &actionSet); err != nil {
+			return helpers.BadRequest("invalid_actions", fmt.Sprintf("The actions of command %s are invalid.", entry.Name))
+		}
+
+		rawParameters, err := json.Marshal(entry.Parameters)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal parameters: %w", err)
+		}
+
+		settings, err := customCommandSettingsFromWire(entry.NameLocalizations, entry.DescriptionLocalizations, entry.DefaultMemberPermissions, entry.Contexts)
+		if err != nil {
+			return err
+		}
+
+		cmd := pgmodel.CustomCommand{
+			ID:          util.UniqueID(),
+			GuildID:     guildID,
+			Name:        entry.Name,
+			Description: entry.Description,
+			Enabled:     entry.Enabled,
+			Parameters:  rawParameters,
+			Actions:     entry.Actions,
+			DerivedPermissions: pqtype.NullRawMessage{
+				Valid:      true,
+				RawMessage: rawDerivedPerms,
+			},
+			CreatedAt:                time.Now().UTC(),
+			UpdatedAt:                time.Now().UTC(),
+			NameLocalizations:        settings.nameLocalizations,
+			DescriptionLocalizations: settings.descriptionLocalizations,
+			DefaultMemberPermissions: settings.defaultMemberPermissions,
+			Contexts:                 settings.contexts,
+			Type:                     int16(entry.Type),
+		}
+
+		missing, err := h.missingActionReferences(c.Context(), guildID, cmd.Name, actionSet)
+		if err != nil {
+			return err
+		}
+
+		i := slices.IndexFunc(existing, func(e pgmodel.CustomCommand) bool {
+			return e.Type == cmd.Type && e.Name == cmd.Name
+		})
+		if i == -1 {
+			merged = append(merged, cmd)
+			missingRefs = append(missingRefs, missing...)
+			inserts = append(inserts, pgmodel.InsertCustomCommandParams{
+				ID:                       cmd.ID,
+				GuildID:                  cmd.GuildID,
+				Name:                     cmd.Name,
+				Description:              cmd.Description,
+				Parameters:               cmd.Parameters,
+				Actions:                  cmd.Actions,
+				DerivedPermissions:       cmd.DerivedPermissions,
+				CreatedAt:                cmd.CreatedAt,
+				UpdatedAt:                cmd.UpdatedAt,
+				NameLocalizations:        cmd.NameLocalizations,
+				DescriptionLocalizations: cmd.DescriptionLocalizations,
+				DefaultMemberPermissions: cmd.DefaultMemberPermissions,
+				Contexts:                 cmd.Contexts,
+				Type:                     cmd.Type,
+				Enabled:                  cmd.Enabled,
+			})
+			continue
+		}
+
+		switch req.OnConflict {
+		case wire.CustomCommandImportConflictSkip:
+			skipped = append(skipped, cmd.Name)
+		case wire.CustomCommandImportConflictReplace:
+			cmd.ID = existing[i].ID
+			merged[i] = cmd
+			missingRefs = append(missingRefs, missing...)
+			updates = append(updates, pgmodel.UpdateCustomCommandParams{
+				ID:                       cmd.ID,
+				GuildID:                  cmd.GuildID,
+				Name:                     cmd.Name,
+				Description:              cmd.Description,
+				Enabled:                  cmd.Enabled,
+				Parameters:               cmd.Parameters,
+				Actions:                  cmd.Actions,
+				DerivedPermissions:       cmd.DerivedPermissions,
+				UpdatedAt:                cmd.UpdatedAt,
+				NameLocalizations:        cmd.NameLocalizations,
+				DescriptionLocalizations: cmd.DescriptionLocalizations,
+				DefaultMemberPermissions: cmd.DefaultMemberPermissions,
+				Contexts:                 cmd.Contexts,
+				Type:                     cmd.Type,
+			})
+		default:
+			return &wire.Error{
+				Code:    "name_collision",
+				Message: fmt.Sprintf("There already is a command with the name %s, choose to skip or replace existing commands.", cmd.Name),
+				Data: &NameCollisionError{
+					FirstName:  cmd.Name,
+					SecondName: existing[i].Name,
+				},
+			}
+		}
+	}
+
+	// Guilds that are above the limit after a downgrade can still replace their existing commands
+	if len(inserts) != 0 && len(merged) > features.MaxCustomCommands {
+		return helpers.Forbidden("insufficient_plan", fmt.Sprintf(
+			"Importing these commands would exceed the maximum of %d custom commands for your plan!",
+			features.MaxCustomCommands,
+		))
+	}
+
+	// Catch collisions between commands and their subcommands before anything is written
+	collision, _ := commandsToPayload(merged)
+	if collision != nil {
+		return &wire.Error{
+			Code:    "name_collision",
+			Message: "There are name collisions between the imported commands and your existing commands.",
+			Data:    collision,
+		}
+	}
+
+	commands, err := h.pg.ImportCustomCommands(c.Context(), inserts, updates)
+	if err != nil {
+		return fmt.Errorf("Failed to import custom commands: %w", err)
+	}
+
+	res := wire.CustomCommandsImportResponseDataWire{
+		Created:           make([]wire.CustomCommandWire, 0, len(inserts)),
+		Updated:           make([]wire.CustomCommandWire, 0, len(updates)),
+		Skipped:           skipped,
+		MissingReferences: missingRefs,
+	}
+	for i, cmd := range commands {
+		w, err := customCommandToWire(cmd)
+		if err != nil {
+			return err
+		}
+
+		// The store writes the updates before the inserts
+		if i < len(updates) {
+			res.Updated = append(res.Updated, w)
+		} else {
+			res.Created = append(res.Created, w)
+		}
+	}
+
+	return c.JSON(wire.CustomCommandsImportResponseWire{
+		Success: true,
+		Data:    res,
+	})
+}
+
+// missingActionReferences returns the roles and saved messages that the actions refer to but that don't exist on the guild.
+// Bundles usually come from another guild, so the command is still imported and the actions have to be updated afterwards.
+func (h *CustomBotsHandler) missingActionReferences(ctx context.Context, guildID string, commandName string, actionSet actions.ActionSet) ([]wire.CustomCommandImportMissingReferenceWire, error) {
+	var res []wire.CustomCommandImportMissingReferenceWire
+
+	for _, action := range actionSet.Actions {
+		switch action.Type {
+		case actions.ActionTypeAddRole, actions.ActionTypeRemoveRole, actions.ActionTypeToggleRole:
+			_, err := h.bot.State.Role(guildID, action.TargetID)
+			if err != nil {
+				if err != discordgo.ErrStateNotFound {
+					return nil, err
+				}
+
+				res = append(res, wire.CustomCommandImportMissingReferenceWire{
+					Command:  commandName,
+					Type:     wire.CustomCommandImportReferenceRole,
+					TargetID: action.TargetID,
+				})
+			}
+		case actions.ActionTypeSavedMessageResponse, actions.ActionTypeSavedMessageDM, actions.ActionTypeSavedMessageEdit:
+			_, err := h.pg.Q.GetSavedMessageForGuild(ctx, pgmodel.GetSavedMessageForGuildParams{
+				GuildID: sql.NullString{Valid: true, String: guildID},
+				ID:      action.TargetID,
+			})
+			if err != nil {
+				if err != sql.ErrNoRows {
+					return nil, fmt.Errorf("Failed to retrieve saved message: %w", err)
+				}
+
+				res = append(res, wire.CustomCommandImportMissingReferenceWire{
+					Command:  commandName,
+					Type:     wire.CustomCommandImportReferenceSavedMessage,
+					TargetID: action.TargetID,
+				})
+			}
+		}
+	}
+
+	return res, nil
+}
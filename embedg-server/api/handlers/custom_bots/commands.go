@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,23 +33,11 @@ func (h *CustomBotsHandler) HandleListCustomCommands(c *fiber.Ctx) error {
 
 	res := make([]wire.CustomCommandWire, 0, len(commands))
 	for _, cmd := range commands {
-		var parameters []wire.CustomCommandParameterWire
-		err := json.Unmarshal(cmd.Parameters, &parameters)
+		w, err := customCommandToWire(cmd)
 		if err != nil {
-			return fmt.Errorf("Failed to unmarshal command parameters: %w", err)
+			return err
 		}
-
-		res = append(res, wire.CustomCommandWire{
-			ID:          cmd.ID,
-			Name:        cmd.Name,
-			Description: cmd.Description,
-			Enabled:     cmd.Enabled,
-			Parameters:  parameters,
-			Actions:     cmd.Actions,
-			CreatedAt:   cmd.CreatedAt,
-			UpdatedAt:   cmd.UpdatedAt,
-			DeployedAt:  null.Time{NullTime: cmd.DeployedAt},
-		})
+		res = append(res, w)
 	}
 
 	return c.JSON(wire.CustomCommandsListResponseWire{
@@ -74,25 +63,14 @@ func (h *CustomBotsHandler) HandleGetCustomCommand(c *fiber.Ctx) error {
 		return err
 	}
 
-	var parameters []wire.CustomCommandParameterWire
-	err = json.Unmarshal(command.Parameters, &parameters)
+	res, err := customCommandToWire(command)
 	if err != nil {
-		return fmt.Errorf("Failed to unmarshal command parameters: %w", err)
+		return err
 	}
 
 	return c.JSON(wire.CustomCommandGetResponseWire{
 		Success: true,
-		Data: wire.CustomCommandWire{
-			ID:          command.ID,
-			Name:        command.Name,
-			Description: command.Description,
-			Enabled:     command.Enabled,
-			Parameters:  parameters,
-			Actions:     command.Actions,
-			CreatedAt:   command.CreatedAt,
-			UpdatedAt:   command.UpdatedAt,
-			DeployedAt:  null.Time{NullTime: command.DeployedAt},
-		},
+		Data:    res,
 	})
 }
 
@@ -144,6 +122,11 @@ func (h *CustomBotsHandler) HandleCreateCustomCommand(c *fiber.Ctx, req wire.Cus
 		return err
 	}
 
+	settings, err := customCommandSettingsFromWire(req.NameLocalizations, req.DescriptionLocalizations, req.DefaultMemberPermissions, req.Contexts)
+	if err != nil {
+		return err
+	}
+
 	command, err := h.pg.Q.InsertCustomCommand(c.Context(), pgmodel.InsertCustomCommandParams{
 		ID:          util.UniqueID(),
 		GuildID:     guildID,
@@ -155,26 +138,27 @@ func (h *CustomBotsHandler) HandleCreateCustomCommand(c *fiber.Ctx, req wire.Cus
 			Valid:      true,
 			RawMessage: rawDerivedPerms,
 		},
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
+		CreatedAt:                time.Now().UTC(),
+		UpdatedAt:                time.Now().UTC(),
+		NameLocalizations:        settings.nameLocalizations,
+		DescriptionLocalizations: settings.descriptionLocalizations,
+		DefaultMemberPermissions: settings.defaultMemberPermissions,
+		Contexts:                 settings.contexts,
+		Type:                     int16(req.Type),
+		Enabled:                  true,
 	})
 	if err != nil {
 		return err
 	}
 
+	res, err := customCommandToWire(command)
+	if err != nil {
+		return err
+	}
+
 	return c.JSON(wire.CustomCommandCreateResponseWire{
 		Success: true,
-		Data: wire.CustomCommandWire{
-			ID:          command.ID,
-			Name:        command.Name,
-			Description: command.Description,
-			Enabled:     command.Enabled,
-			Parameters:  req.Parameters,
-			Actions:     command.Actions,
-			CreatedAt:   command.CreatedAt,
-			UpdatedAt:   command.UpdatedAt,
-			DeployedAt:  null.Time{NullTime: command.DeployedAt},
-		},
+		Data:    res,
 	})
 }
 
@@ -217,6 +201,11 @@ func (h *CustomBotsHandler) HandleUpdateCustomCommand(c *fiber.Ctx, req wire.Cus
 		return fmt.Errorf("Failed to marshal parameters: %w", err)
 	}
 
+	settings, err := customCommandSettingsFromWire(req.NameLocalizations, req.DescriptionLocalizations, req.DefaultMemberPermissions, req.Contexts)
+	if err != nil {
+		return err
+	}
+
 	command, err := h.pg.Q.UpdateCustomCommand(c.Context(), pgmodel.UpdateCustomCommandParams{
 		ID:          c.Params("commandID"),
 		GuildID:     guildID,
@@ -229,24 +218,25 @@ func (h *CustomBotsHandler) HandleUpdateCustomCommand(c *fiber.Ctx, req wire.Cus
 			Valid:      true,
 			RawMessage: rawDerivedPerms,
 		},
-		UpdatedAt: time.Now().UTC(),
+		UpdatedAt:                time.Now().UTC(),
+		NameLocalizations:        settings.nameLocalizations,
+		DescriptionLocalizations: settings.descriptionLocalizations,
+		DefaultMemberPermissions: settings.defaultMemberPermissions,
+		Contexts:                 settings.contexts,
+		Type:                     int16(req.Type),
 	})
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(wire.CustomCommandCreateResponseWire{
+	res, err := customCommandToWire(command)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(wire.CustomCommandUpdateResponseWire{
 		Success: true,
-		Data: wire.CustomCommandWire{
-			ID:          command.ID,
-			Name:        command.Name,
-			Description: command.Description,
-			Enabled:     command.Enabled,
-			Parameters:  req.Parameters,
-			Actions:     command.Actions,
-			CreatedAt:   command.CreatedAt,
-			UpdatedAt:   command.UpdatedAt,
-		},
+		Data:    res,
 	})
 }
 
@@ -272,67 +262,101 @@ func (h *CustomBotsHandler) HandleDeleteCustomCommand(c *fiber.Ctx) error {
 	})
 }
 
-func (h *CustomBotsHandler) HandleDeployCustomCommands(c *fiber.Ctx) error {
-	guildID := c.Query("guild_id")
-	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
-		return err
+func customCommandToWire(cmd pgmodel.CustomCommand) (wire.CustomCommandWire, error) {
+	var parameters []wire.CustomCommandParameterWire
+	if err := json.Unmarshal(cmd.Parameters, &parameters); err != nil {
+		return wire.CustomCommandWire{}, fmt.Errorf("Failed to unmarshal command parameters: %w", err)
 	}
 
-	features, err := h.planStore.GetPlanFeaturesForGuild(c.Context(), guildID)
-	if err != nil {
-		return err
+	var nameLocalizations, descriptionLocalizations map[string]string
+	if err := json.Unmarshal(cmd.NameLocalizations, &nameLocalizations); err != nil {
+		return wire.CustomCommandWire{}, fmt.Errorf("Failed to unmarshal command name localizations: %w", err)
+	}
+	if err := json.Unmarshal(cmd.DescriptionLocalizations, &descriptionLocalizations); err != nil {
+		return wire.CustomCommandWire{}, fmt.Errorf("Failed to unmarshal command description localizations: %w", err)
 	}
 
-	if !features.CustomBot {
-		return helpers.Forbidden("insufficient_plan", "This feature is not available on your plan!")
+	var defaultMemberPermissions null.String
+	if cmd.DefaultMemberPermissions.Valid {
+		defaultMemberPermissions = null.StringFrom(strconv.FormatInt(cmd.DefaultMemberPermissions.Int64, 10))
 	}
 
-	customBot, err := h.pg.Q.GetCustomBotByGuildID(c.Context(), guildID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return helpers.NotFound("not_configured", "There is no custom bot configured right now, you need to configure one first.")
+	var contexts []int
+	if cmd.Contexts != nil {
+		contexts = make([]int, len(cmd.Contexts))
+		for i, c := range cmd.Contexts {
+			contexts[i] = int(c)
 		}
-		return fmt.Errorf("Failed to retrieve custom bot: %w", err)
 	}
 
-	commands, err := h.pg.Q.GetCustomCommands(c.Context(), guildID)
-	if err != nil {
-		return fmt.Errorf("Failed to retrieve custom commands: %w", err)
-	}
+	return wire.CustomCommandWire{
+		ID:                       cmd.ID,
+		Type:                     int(cmd.Type),
+		Name:                     cmd.Name,
+		Description:              cmd.Description,
+		NameLocalizations:        nameLocalizations,
+		DescriptionLocalizations: descriptionLocalizations,
+		DefaultMemberPermissions: defaultMemberPermissions,
+		Contexts:                 contexts,
+		Enabled:                  cmd.Enabled,
+		Parameters:               parameters,
+		Actions:                  cmd.Actions,
+		CreatedAt:                cmd.CreatedAt,
+		UpdatedAt:                cmd.UpdatedAt,
+		DeployedAt:               null.Time{NullTime: cmd.DeployedAt},
+	}, nil
+}
 
-	collision, payload := commandsToPayload(commands)
-	if collision != nil {
-		return &wire.Error{
-			Code:    "name_collision",
-			Message: "There are name collisions in your custom commands, please fix them first.",
-			Data:    collision,
-		}
+// customCommandSettings are the command level settings in the format they are stored in.
+type customCommandSettings struct {
+	nameLocalizations        json.RawMessage
+	descriptionLocalizations json.RawMessage
+	defaultMemberPermissions sql.NullInt64
+	contexts                 []int32
+}
+
+func customCommandSettingsFromWire(
+	nameLocalizations map[string]string,
+	descriptionLocalizations map[string]string,
+	defaultMemberPermissions null.String,
+	contexts []int,
+) (customCommandSettings, error) {
+	var res customCommandSettings
+
+	if nameLocalizations == nil {
+		nameLocalizations = map[string]string{}
+	}
+	if descriptionLocalizations == nil {
+		descriptionLocalizations = map[string]string{}
 	}
 
-	session, err := discordgo.New("Bot " + customBot.Token)
+	var err error
+	res.nameLocalizations, err = json.Marshal(nameLocalizations)
 	if err != nil {
-		return fmt.Errorf("Failed to create custom bot session: %w", err)
+		return res, fmt.Errorf("Failed to marshal name localizations: %w", err)
 	}
-
-	_, err = session.ApplicationCommandBulkOverwrite(customBot.ApplicationID, guildID, payload)
+	res.descriptionLocalizations, err = json.Marshal(descriptionLocalizations)
 	if err != nil {
-		return fmt.Errorf("Failed to deploy commands: %w", err)
+		return res, fmt.Errorf("Failed to marshal description localizations: %w", err)
 	}
 
-	_, err = h.pg.Q.SetCustomCommandsDeployedAt(c.Context(), pgmodel.SetCustomCommandsDeployedAtParams{
-		GuildID: guildID,
-		DeployedAt: sql.NullTime{
-			Time:  time.Now().UTC(),
-			Valid: true,
-		},
-	})
-	if err != nil {
-		return fmt.Errorf("Failed to set deployed_at: %w", err)
+	if defaultMemberPermissions.Valid {
+		perms, err := strconv.ParseInt(defaultMemberPermissions.String, 10, 64)
+		if err != nil {
+			return res, helpers.BadRequest("invalid_permissions", "The default member permissions are invalid.")
+		}
+		res.defaultMemberPermissions = sql.NullInt64{Int64: perms, Valid: true}
 	}
 
-	return c.JSON(wire.CustomCommandsDeployResponseWire{
-		Success: true,
-	})
+	// No contexts means that Discord's default is used
+	if contexts != nil {
+		res.contexts = make([]int32, len(contexts))
+		for i, c := range contexts {
+			res.contexts[i] = int32(c)
+		}
+	}
+
+	return res, nil
 }
 
 func commandsToPayload(commands []pgmodel.CustomCommand) (error, []*discordgo.ApplicationCommand) {
@@ -349,17 +373,43 @@ func commandsToPayload(commands []pgmodel.CustomCommand) (error, []*discordgo.Ap
 
 		options := make([]*discordgo.ApplicationCommandOption, 0, len(cmd.Parameters))
 		for _, param := range parameters {
-			options = append(options, &discordgo.ApplicationCommandOption{
-				Type:        discordgo.ApplicationCommandOptionType(param.Type),
-				Name:        param.Name,
-				Description: param.Description,
-				Required:    true,
-			})
+			options = append(options, parameterToOption(param))
+		}
+
+		var nameLocalizations, descriptionLocalizations map[discordgo.Locale]string
+		if err := json.Unmarshal(cmd.NameLocalizations, &nameLocalizations); err != nil {
+			return fmt.Errorf("Failed to unmarshal command name localizations: %w", err), nil
+		}
+		if err := json.Unmarshal(cmd.DescriptionLocalizations, &descriptionLocalizations); err != nil {
+			return fmt.Errorf("Failed to unmarshal command description localizations: %w", err), nil
+		}
+
+		if cmd.Type == wire.CustomCommandTypeUser || cmd.Type == wire.CustomCommandTypeMessage {
+			// Context menu commands can't be grouped and only collide with commands of the same type
+			for _, c := range res {
+				if c.Type == discordgo.ApplicationCommandType(cmd.Type) && c.Name == cmd.Name {
+					return &NameCollisionError{
+						FirstName:  cmd.Name,
+						SecondName: c.Name,
+					}, nil
+				}
+			}
+
+			contextCMD := &discordgo.ApplicationCommand{
+				Type: discordgo.ApplicationCommandType(cmd.Type),
+				Name: cmd.Name,
+			}
+			if len(nameLocalizations) != 0 {
+				contextCMD.NameLocalizations = &nameLocalizations
+			}
+			setCommandPermissions(contextCMD, cmd)
+			res = append(res, contextCMD)
+			continue
 		}
 
 		var rootCMD *discordgo.ApplicationCommand
 		for _, c := range res {
-			if c.Name == nameParts[0] {
+			if c.Type == discordgo.ChatApplicationCommand && c.Name == nameParts[0] {
 				if len(nameParts) == 1 {
 					return &NameCollisionError{
 						FirstName:  cmd.Name,
@@ -378,8 +428,19 @@ func commandsToPayload(commands []pgmodel.CustomCommand) (error, []*discordgo.Ap
 				Name:        nameParts[0],
 				Description: cmd.Description,
 			}
+
+			// Permissions and contexts can only be set for the top-level command,
+			// so the first command in a group decides them for all of its subcommands
+			setCommandPermissions(rootCMD, cmd)
+
 			if len(nameParts) == 1 {
 				rootCMD.Options = options
+				if len(nameLocalizations) != 0 {
+					rootCMD.NameLocalizations = &nameLocalizations
+				}
+				if len(descriptionLocalizations) != 0 {
+					rootCMD.DescriptionLocalizations = &descriptionLocalizations
+				}
 			}
 			res = append(res, rootCMD)
 		}
@@ -408,6 +469,8 @@ func commandsToPayload(commands []pgmodel.CustomCommand) (error, []*discordgo.Ap
 				}
 				if len(nameParts) == 2 {
 					secondCMD.Options = options
+					secondCMD.NameLocalizations = nameLocalizations
+					secondCMD.DescriptionLocalizations = descriptionLocalizations
 				}
 				rootCMD.Options = append(rootCMD.Options, secondCMD)
 			}
@@ -425,10 +488,12 @@ func commandsToPayload(commands []pgmodel.CustomCommand) (error, []*discordgo.Ap
 
 			secondCMD.Type = discordgo.ApplicationCommandOptionSubCommandGroup
 			secondCMD.Options = append(secondCMD.Options, &discordgo.ApplicationCommandOption{
-				Type:        discordgo.ApplicationCommandOptionSubCommand,
-				Name:        nameParts[2],
-				Description: cmd.Description,
-				Options:     options,
+				Type:                     discordgo.ApplicationCommandOptionSubCommand,
+				Name:                     nameParts[2],
+				NameLocalizations:        nameLocalizations,
+				Description:              cmd.Description,
+				DescriptionLocalizations: descriptionLocalizations,
+				Options:                  options,
 			})
 		}
 	}
@@ -436,6 +501,82 @@ func commandsToPayload(commands []pgmodel.CustomCommand) (error, []*discordgo.Ap
 	return nil, res
 }
 
+func setCommandPermissions(c *discordgo.ApplicationCommand, cmd pgmodel.CustomCommand) {
+	if cmd.DefaultMemberPermissions.Valid {
+		perms := cmd.DefaultMemberPermissions.Int64
+		c.DefaultMemberPermissions = &perms
+	}
+	if cmd.Contexts != nil {
+		contexts := make([]discordgo.InteractionContextType, len(cmd.Contexts))
+		for i, ctx := range cmd.Contexts {
+			contexts[i] = discordgo.InteractionContextType(ctx)
+		}
+		c.Contexts = &contexts
+	}
+}
+
+func parameterToOption(param wire.CustomCommandParameterWire) *discordgo.ApplicationCommandOption {
+	opt := &discordgo.ApplicationCommandOption{
+		Type:         discordgo.ApplicationCommandOptionType(param.Type),
+		Name:         param.Name,
+		Description:  param.Description,
+		Required:     param.IsRequired(),
+		Autocomplete: param.Autocomplete != nil,
+	}
+
+	if len(param.NameLocalizations) != 0 {
+		opt.NameLocalizations = make(map[discordgo.Locale]string, len(param.NameLocalizations))
+		for locale, name := range param.NameLocalizations {
+			opt.NameLocalizations[discordgo.Locale(locale)] = name
+		}
+	}
+	if len(param.DescriptionLocalizations) != 0 {
+		opt.DescriptionLocalizations = make(map[discordgo.Locale]string, len(param.DescriptionLocalizations))
+		for locale, description := range param.DescriptionLocalizations {
+			opt.DescriptionLocalizations[discordgo.Locale(locale)] = description
+		}
+	}
+
+	for _, choice := range param.Choices {
+		c := &discordgo.ApplicationCommandOptionChoice{
+			Name:  choice.Name,
+			Value: choice.Value,
+		}
+		if len(choice.NameLocalizations) != 0 {
+			c.NameLocalizations = make(map[discordgo.Locale]string, len(choice.NameLocalizations))
+			for locale, name := range choice.NameLocalizations {
+				c.NameLocalizations[discordgo.Locale(locale)] = name
+			}
+		}
+		opt.Choices = append(opt.Choices, c)
+	}
+
+	switch opt.Type {
+	case discordgo.ApplicationCommandOptionInteger, discordgo.ApplicationCommandOptionNumber:
+		if param.MinValue.Valid {
+			minValue := param.MinValue.Float64
+			opt.MinValue = &minValue
+		}
+		if param.MaxValue.Valid {
+			opt.MaxValue = param.MaxValue.Float64
+		}
+	case discordgo.ApplicationCommandOptionString:
+		if param.MinLength.Valid {
+			minLength := int(param.MinLength.Int64)
+			opt.MinLength = &minLength
+		}
+		if param.MaxLength.Valid {
+			opt.MaxLength = int(param.MaxLength.Int64)
+		}
+	case discordgo.ApplicationCommandOptionChannel:
+		for _, t := range param.ChannelTypes {
+			opt.ChannelTypes = append(opt.ChannelTypes, discordgo.ChannelType(t))
+		}
+	}
+
+	return opt
+}
+
 type NameCollisionError struct {
 	FirstName  string `json:"first_name"`
 	SecondName string `json:"second_name"`
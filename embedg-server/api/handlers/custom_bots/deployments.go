@@ -0,0 +1,416 @@
+package custom_bots
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/merlinfuchs/discordgo"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/helpers"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/session"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/wire"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/merlinfuchs/embed-generator/embedg-server/util"
+	"gopkg.in/guregu/null.v4"
+)
+
+// maxCustomCommandDeployments is the number of deployments that are kept per guild.
+const maxCustomCommandDeployments = 25
+
+// HandleGetCustomCommandsDeployPlan shows the changes that deploying the custom commands would make to the registered commands.
+func (h *CustomBotsHandler) HandleGetCustomCommandsDeployPlan(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	customBot, err := h.deploymentCustomBot(c, guildID)
+	if err != nil {
+		return err
+	}
+
+	local, err := h.localCommandsPayload(c.Context(), guildID)
+	if err != nil {
+		return err
+	}
+
+	botSession, err := discordgo.New("Bot " + customBot.Token)
+	if err != nil {
+		return fmt.Errorf("Failed to create custom bot session: %w", err)
+	}
+
+	remote, err := registeredCommands(c.Context(), botSession, customBot)
+	if err != nil {
+		return err
+	}
+
+	diff := diffCommands(local, remote)
+
+	return c.JSON(wire.CustomCommandsDeployPlanResponseWire{
+		Success: true,
+		Data: wire.CustomCommandsDeployPlanWire{
+			InSync:  diff.Empty(),
+			Changes: commandChanges(diff),
+		},
+	})
+}
+
+func (h *CustomBotsHandler) HandleDeployCustomCommands(c *fiber.Ctx) error {
+	session := c.Locals("session").(*session.Session)
+
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	customBot, err := h.deploymentCustomBot(c, guildID)
+	if err != nil {
+		return err
+	}
+
+	local, err := h.localCommandsPayload(c.Context(), guildID)
+	if err != nil {
+		return err
+	}
+
+	res, err := h.deployCommands(c.Context(), customBot, session.UserID, local, sql.NullString{})
+	if err != nil {
+		return err
+	}
+
+	if err := h.setCommandsDeployedAt(c.Context(), guildID, true); err != nil {
+		return err
+	}
+
+	return c.JSON(wire.CustomCommandsDeployResponseWire{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *CustomBotsHandler) HandleListCustomCommandDeployments(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	deployments, err := h.pg.Q.GetCustomCommandDeployments(c.Context(), pgmodel.GetCustomCommandDeploymentsParams{
+		GuildID: guildID,
+		Limit:   maxCustomCommandDeployments,
+	})
+	if err != nil {
+		return err
+	}
+
+	res := make([]wire.CustomCommandDeploymentWire, 0, len(deployments))
+	for _, deployment := range deployments {
+		w, err := customCommandDeploymentToWire(deployment)
+		if err != nil {
+			return err
+		}
+		res = append(res, w)
+	}
+
+	return c.JSON(wire.CustomCommandDeploymentsListResponseWire{
+		Success: true,
+		Data:    res,
+	})
+}
+
+// HandleRollbackCustomCommandDeployment registers the commands of a previous deployment again.
+// Only the commands that are registered with Discord are rolled back, the custom commands themselves are left unchanged.
+func (h *CustomBotsHandler) HandleRollbackCustomCommandDeployment(c *fiber.Ctx) error {
+	session := c.Locals("session").(*session.Session)
+
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	customBot, err := h.deploymentCustomBot(c, guildID)
+	if err != nil {
+		return err
+	}
+
+	deployment, err := h.pg.Q.GetCustomCommandDeployment(c.Context(), pgmodel.GetCustomCommandDeploymentParams{
+		ID:      c.Params("deploymentID"),
+		GuildID: guildID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return helpers.NotFound("unknown_deployment", "No deployment found with this ID")
+		}
+		return err
+	}
+
+	var commands []*discordgo.ApplicationCommand
+	if err := json.Unmarshal(deployment.Commands, &commands); err != nil {
+		return fmt.Errorf("Failed to unmarshal deployed commands: %w", err)
+	}
+
+	// The commands may have been registered by another bot, so the fields that are set by Discord are cleared
+	for _, cmd := range commands {
+		cmd.ID = ""
+		cmd.ApplicationID = ""
+		cmd.GuildID = ""
+		cmd.Version = ""
+	}
+
+	res, err := h.deployCommands(c.Context(), customBot, session.UserID, commands, sql.NullString{
+		String: deployment.ID,
+		Valid:  true,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The registered commands differ from the local commands now, clearing deployed_at shows all of them as
+	// not deployed until they are deployed again and the deploy plan lists the differences
+	if err := h.setCommandsDeployedAt(c.Context(), guildID, false); err != nil {
+		return err
+	}
+
+	return c.JSON(wire.CustomCommandDeploymentRollbackResponseWire{
+		Success: true,
+		Data:    res,
+	})
+}
+
+// setCommandsDeployedAt marks the custom commands of the guild as deployed so they are no longer shown as changed,
+// or as not deployed when the registered commands no longer match them.
+func (h *CustomBotsHandler) setCommandsDeployedAt(ctx context.Context, guildID string, deployed bool) error {
+	_, err := h.pg.Q.SetCustomCommandsDeployedAt(ctx, pgmodel.SetCustomCommandsDeployedAtParams{
+		GuildID: guildID,
+		DeployedAt: sql.NullTime{
+			Time:  time.Now().UTC(),
+			Valid: deployed,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to set deployed_at: %w", err)
+	}
+	return nil
+}
+
+// deploymentCustomBot returns the custom bot of the guild if the guild is allowed to deploy commands.
+func (h *CustomBotsHandler) deploymentCustomBot(c *fiber.Ctx, guildID string) (pgmodel.CustomBot, error) {
+	features, err := h.planStore.GetPlanFeaturesForGuild(c.Context(), guildID)
+	if err != nil {
+		return pgmodel.CustomBot{}, err
+	}
+
+	if !features.CustomBot {
+		return pgmodel.CustomBot{}, helpers.Forbidden("insufficient_plan", "This feature is not available on your plan!")
+	}
+
+	customBot, err := h.pg.GetCustomBotByGuildID(c.Context(), guildID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return pgmodel.CustomBot{}, helpers.NotFound("not_configured", "There is no custom bot configured right now, you need to configure one first.")
+		}
+		return pgmodel.CustomBot{}, fmt.Errorf("Failed to retrieve custom bot: %w", err)
+	}
+
+	return customBot, nil
+}
+
+// localCommandsPayload returns the custom commands of the guild in the format that is registered with Discord.
+func (h *CustomBotsHandler) localCommandsPayload(ctx context.Context, guildID string) ([]*discordgo.ApplicationCommand, error) {
+	commands, err := h.pg.Q.GetCustomCommands(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve custom commands: %w", err)
+	}
+
+	collision, payload := commandsToPayload(commands)
+	if collision != nil {
+		return nil, &wire.Error{
+			Code:    "name_collision",
+			Message: "There are name collisions in your custom commands, please fix them first.",
+			Data:    collision,
+		}
+	}
+
+	return payload, nil
+}
+
+// deployCommands applies the changes that are needed to register the given commands and records them as a deployment.
+func (h *CustomBotsHandler) deployCommands(
+	ctx context.Context,
+	customBot pgmodel.CustomBot,
+	userID string,
+	commands []*discordgo.ApplicationCommand,
+	rollbackOf sql.NullString,
+) (wire.CustomCommandsDeployResultWire, error) {
+	botSession, err := discordgo.New("Bot " + customBot.Token)
+	if err != nil {
+		return wire.CustomCommandsDeployResultWire{}, fmt.Errorf("Failed to create custom bot session: %w", err)
+	}
+
+	remote, err := registeredCommands(ctx, botSession, customBot)
+	if err != nil {
+		return wire.CustomCommandsDeployResultWire{}, err
+	}
+
+	diff := diffCommands(commands, remote)
+	res := wire.CustomCommandsDeployResultWire{
+		Changes: commandChanges(diff),
+	}
+	if diff.Empty() {
+		return res, nil
+	}
+
+	applyErr := applyCommandDiff(ctx, botSession, customBot, diff)
+
+	// The registered commands are fetched again to record the IDs of all of them, including the unchanged ones
+	registered, err := registeredCommands(ctx, botSession, customBot)
+	if err != nil {
+		if applyErr != nil {
+			return res, applyErr
+		}
+		return res, err
+	}
+
+	// When applying the changes failed midway, the commands that have been registered until then
+	// are recorded as a partial deployment so that it's possible to roll back to the previous one
+	partial := applyErr != nil
+	if partial {
+		res.Changes = commandChanges(diffCommands(registered, remote))
+	}
+
+	rawCommands, err := json.Marshal(registered)
+	if err != nil {
+		return res, fmt.Errorf("Failed to marshal deployed commands: %w", err)
+	}
+
+	rawChanges, err := json.Marshal(res.Changes)
+	if err != nil {
+		return res, fmt.Errorf("Failed to marshal deployment changes: %w", err)
+	}
+
+	deployment, err := h.pg.Q.InsertCustomCommandDeployment(ctx, pgmodel.InsertCustomCommandDeploymentParams{
+		ID:            util.UniqueID(),
+		GuildID:       customBot.GuildID,
+		ApplicationID: customBot.ApplicationID,
+		UserID:        userID,
+		Commands:      rawCommands,
+		Changes:       rawChanges,
+		RollbackOf:    rollbackOf,
+		CreatedAt:     time.Now().UTC(),
+		Partial:       partial,
+	})
+	if err != nil {
+		return res, fmt.Errorf("Failed to insert deployment: %w", err)
+	}
+
+	err = h.pg.Q.DeleteOldCustomCommandDeployments(ctx, pgmodel.DeleteOldCustomCommandDeploymentsParams{
+		GuildID: customBot.GuildID,
+		Limit:   maxCustomCommandDeployments,
+	})
+	if err != nil {
+		return res, fmt.Errorf("Failed to delete old deployments: %w", err)
+	}
+
+	res.DeploymentID = null.StringFrom(deployment.ID)
+
+	if partial {
+		return res, &wire.Error{
+			Status:  fiber.StatusBadRequest,
+			Code:    "partial_deployment",
+			Message: fmt.Sprintf("Only some of the commands have been deployed: %s", applyErr),
+			Data:    res,
+		}
+	}
+	return res, nil
+}
+
+// applyCommandDiff deletes, creates and updates the registered commands and stops at the first change that fails.
+func applyCommandDiff(ctx context.Context, botSession *discordgo.Session, customBot pgmodel.CustomBot, diff commandDiff) error {
+	// Deleting first frees up slots in case the guild is close to the command limit
+	for _, cmd := range diff.Delete {
+		err := botSession.ApplicationCommandDelete(customBot.ApplicationID, customBot.GuildID, cmd.ID, discordgo.WithContext(ctx))
+		if err != nil && !util.IsDiscordRestErrorCode(err, discordgo.ErrCodeUnknownApplicationCommand) {
+			return fmt.Errorf("Failed to delete command %s: %w", cmd.Name, err)
+		}
+	}
+
+	// Creating a command with the same type and name as a registered command overwrites it,
+	// unlike editing it this also resets the fields that have been removed
+	for _, cmd := range slices.Concat(diff.Create, diff.Update) {
+		_, err := botSession.ApplicationCommandCreate(customBot.ApplicationID, customBot.GuildID, cmd, discordgo.WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("Failed to deploy command %s: %w", cmd.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// registeredCommands returns the commands that are currently registered with Discord for the guild of the custom bot.
+func registeredCommands(ctx context.Context, botSession *discordgo.Session, customBot pgmodel.CustomBot) ([]*discordgo.ApplicationCommand, error) {
+	commands, err := botSession.ApplicationCommands(customBot.ApplicationID, customBot.GuildID, discordgo.WithContext(ctx))
+	if err != nil {
+		if derr, ok := err.(*discordgo.RESTError); ok && derr.Response.StatusCode == 401 {
+			return nil, helpers.BadRequest("invalid_token", "The token of the custom bot is invalid.")
+		}
+		return nil, fmt.Errorf("Failed to retrieve registered commands: %w", err)
+	}
+	return commands, nil
+}
+
+func commandChanges(diff commandDiff) []wire.CustomCommandChangeWire {
+	res := make([]wire.CustomCommandChangeWire, 0, len(diff.Create)+len(diff.Update)+len(diff.Delete))
+	for _, cmd := range diff.Create {
+		res = append(res, commandChange(wire.CustomCommandChangeCreate, cmd))
+	}
+	for _, cmd := range diff.Update {
+		res = append(res, commandChange(wire.CustomCommandChangeUpdate, cmd))
+	}
+	for _, cmd := range diff.Delete {
+		res = append(res, commandChange(wire.CustomCommandChangeDelete, cmd))
+	}
+	return res
+}
+
+func commandChange(action string, cmd *discordgo.ApplicationCommand) wire.CustomCommandChangeWire {
+	return wire.CustomCommandChangeWire{
+		Action: action,
+		Type:   int(commandType(cmd)),
+		Name:   cmd.Name,
+	}
+}
+
+func customCommandDeploymentToWire(deployment pgmodel.CustomCommandDeployment) (wire.CustomCommandDeploymentWire, error) {
+	var commands []*discordgo.ApplicationCommand
+	if err := json.Unmarshal(deployment.Commands, &commands); err != nil {
+		return wire.CustomCommandDeploymentWire{}, fmt.Errorf("Failed to unmarshal deployed commands: %w", err)
+	}
+
+	var changes []wire.CustomCommandChangeWire
+	if err := json.Unmarshal(deployment.Changes, &changes); err != nil {
+		return wire.CustomCommandDeploymentWire{}, fmt.Errorf("Failed to unmarshal deployment changes: %w", err)
+	}
+
+	deployed := make([]wire.CustomCommandDeployedWire, len(commands))
+	for i, cmd := range commands {
+		deployed[i] = wire.CustomCommandDeployedWire{
+			ID:   cmd.ID,
+			Type: int(commandType(cmd)),
+			Name: cmd.Name,
+		}
+	}
+
+	return wire.CustomCommandDeploymentWire{
+		ID:            deployment.ID,
+		ApplicationID: deployment.ApplicationID,
+		UserID:        deployment.UserID,
+		Commands:      deployed,
+		Changes:       changes,
+		RollbackOf:    null.String{NullString: deployment.RollbackOf},
+		Partial:       deployment.Partial,
+		CreatedAt:     deployment.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package custom_bots
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/merlinfuchs/discordgo"
+)
+
+// commandDiff describes the changes that are needed to get from the deployed commands to the local commands.
+type commandDiff struct {
+	// Create contains the local commands that aren't deployed.
+	Create []*discordgo.ApplicationCommand
+	// Update contains the local commands that are deployed but differ from the deployed version.
+	Update []*discordgo.ApplicationCommand
+	// Delete contains the deployed commands that don't exist locally.
+	Delete []*discordgo.ApplicationCommand
+}
+
+func (d commandDiff) Empty() bool {
+	return len(d.Create) == 0 && len(d.Update) == 0 && len(d.Delete) == 0
+}
+
+// diffCommands compares the payload from commandsToPayload with the commands that are deployed to Discord.
+// Commands are matched by type and name, fields that are set by Discord are ignored.
+func diffCommands(local []*discordgo.ApplicationCommand, remote []*discordgo.ApplicationCommand) commandDiff {
+	var diff commandDiff
+
+	for _, l := range local {
+		i := slices.IndexFunc(remote, func(r *discordgo.ApplicationCommand) bool {
+			return sameCommand(l, r)
+		})
+		if i == -1 {
+			diff.Create = append(diff.Create, l)
+		} else if !commandsEqual(l, remote[i]) {
+			diff.Update = append(diff.Update, l)
+		}
+	}
+
+	for _, r := range remote {
+		if !slices.ContainsFunc(local, func(l *discordgo.ApplicationCommand) bool {
+			return sameCommand(l, r)
+		}) {
+			diff.Delete = append(diff.Delete, r)
+		}
+	}
+
+	return diff
+}
+
+func sameCommand(a, b *discordgo.ApplicationCommand) bool {
+	return commandType(a) == commandType(b) && a.Name == b.Name
+}
+
+// commandType treats a missing type as a chat command like Discord does.
+func commandType(c *discordgo.ApplicationCommand) discordgo.ApplicationCommandType {
+	if c.Type == 0 {
+		return discordgo.ChatApplicationCommand
+	}
+	return c.Type
+}
+
+// commandsEqual compares the local command a with the deployed command b.
+func commandsEqual(a, b *discordgo.ApplicationCommand) bool {
+	// Discord fills in the contexts when they aren't set, so they are only compared when they are set locally
+	if a.Contexts != nil && (b.Contexts == nil || !slices.Equal(*a.Contexts, *b.Contexts)) {
+		return false
+	}
+
+	return a.Description == b.Description &&
+		maps.Equal(derefLocalizations(a.NameLocalizations), derefLocalizations(b.NameLocalizations)) &&
+		maps.Equal(derefLocalizations(a.DescriptionLocalizations), derefLocalizations(b.DescriptionLocalizations)) &&
+		equalPtr(a.DefaultMemberPermissions, b.DefaultMemberPermissions) &&
+		commandOptionsEqual(a.Options, b.Options)
+}
+
+func commandOptionsEqual(a, b []*discordgo.ApplicationCommandOption) bool {
+	return slices.EqualFunc(a, b, func(a, b *discordgo.ApplicationCommandOption) bool {
+		return a.Type == b.Type &&
+			a.Name == b.Name &&
+			a.Description == b.Description &&
+			maps.Equal(a.NameLocalizations, b.NameLocalizations) &&
+			maps.Equal(a.DescriptionLocalizations, b.DescriptionLocalizations) &&
+			a.Required == b.Required &&
+			a.Autocomplete == b.Autocomplete &&
+			slices.Equal(a.ChannelTypes, b.ChannelTypes) &&
+			equalPtr(a.MinValue, b.MinValue) &&
+			a.MaxValue == b.MaxValue &&
+			equalPtr(a.MinLength, b.MinLength) &&
+			a.MaxLength == b.MaxLength &&
+			commandOptionChoicesEqual(a.Choices, b.Choices) &&
+			commandOptionsEqual(a.Options, b.Options)
+	})
+}
+
+func commandOptionChoicesEqual(a, b []*discordgo.ApplicationCommandOptionChoice) bool {
+	return slices.EqualFunc(a, b, func(a, b *discordgo.ApplicationCommandOptionChoice) bool {
+		// Values are strings or numbers, numbers from the API and from the stored parameters are both float64
+		return a.Name == b.Name &&
+			maps.Equal(a.NameLocalizations, b.NameLocalizations) &&
+			a.Value == b.Value
+	})
+}
+
+func derefLocalizations(l *map[discordgo.Locale]string) map[discordgo.Locale]string {
+	if l == nil {
+		return nil
+	}
+	return *l
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// commandNames returns the names of the commands.
+func commandNames(commands []*discordgo.ApplicationCommand) []string {
+	res := make([]string, len(commands))
+	for i, c := range commands {
+		res[i] = c.Name
+	}
+	return res
+}
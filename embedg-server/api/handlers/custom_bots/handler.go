@@ -101,7 +101,7 @@ func (h *CustomBotsHandler) HandleConfigureCustomBot(c *fiber.Ctx, req wire.Cust
 		}
 	}
 
-	customBot, err := h.pg.Q.UpsertCustomBot(c.Context(), pgmodel.UpsertCustomBotParams{
+	customBot, err := h.pg.UpsertCustomBot(c.Context(), pgmodel.UpsertCustomBotParams{
 		ID:                util.UniqueID(),
 		GuildID:           guildID,
 		ApplicationID:     app.ID,
@@ -117,6 +117,10 @@ func (h *CustomBotsHandler) HandleConfigureCustomBot(c *fiber.Ctx, req wire.Cust
 		return err
 	}
 
+	if err := h.pg.NotifyCustomBotChanged(c.Context(), guildID); err != nil {
+		log.Error().Err(err).Msg("Failed to notify custom bot change")
+	}
+
 	return c.JSON(wire.CustomBotConfigureResponseWire{
 		Success: true,
 		Data: wire.CustomBotInfoWire{
@@ -176,6 +180,10 @@ func (h *CustomBotsHandler) HandleUpdateCustomBotPresence(c *fiber.Ctx, req wire
 		return err
 	}
 
+	if err := h.pg.NotifyCustomBotChanged(c.Context(), guildID); err != nil {
+		log.Error().Err(err).Msg("Failed to notify custom bot change")
+	}
+
 	return c.JSON(wire.CustomBotUpdatePresenceResponseWire{
 		Success: true,
 		Data:    wire.CustomBotPresenceWire(req),
@@ -196,6 +204,10 @@ func (h *CustomBotsHandler) HandleDisableCustomBot(c *fiber.Ctx) error {
 		return err
 	}
 
+	if err := h.pg.NotifyCustomBotChanged(c.Context(), guildID); err != nil {
+		log.Error().Err(err).Msg("Failed to notify custom bot change")
+	}
+
 	return c.JSON(wire.CustomBotDisableResponseWire{
 		Success: true,
 		Data:    wire.CustomBotDisableResponseDataWire{},
@@ -208,7 +220,7 @@ func (h *CustomBotsHandler) HandleGetCustomBot(c *fiber.Ctx) error {
 		return err
 	}
 
-	customBot, err := h.pg.Q.GetCustomBotByGuildID(c.Context(), guildID)
+	customBot, err := h.pg.GetCustomBotByGuildID(c.Context(), guildID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return helpers.NotFound("not_configured", "There is no custom bot configured right now")
@@ -240,7 +252,7 @@ func (h *CustomBotsHandler) HandleGetCustomBot(c *fiber.Ctx) error {
 	}
 
 	if member != nil {
-		customBot, err = h.pg.Q.UpdateCustomBotUser(c.Context(), pgmodel.UpdateCustomBotUserParams{
+		customBot, err = h.pg.UpdateCustomBotUser(c.Context(), pgmodel.UpdateCustomBotUserParams{
 			GuildID:           guildID,
 			UserName:          member.User.Username,
 			UserDiscriminator: member.User.Discriminator,
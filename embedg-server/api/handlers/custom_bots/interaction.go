@@ -2,24 +2,47 @@ package custom_bots
 
 import (
 	"bytes"
+	"context"
 	"crypto/ed25519"
 	"database/sql"
 	"encoding/hex"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jellydator/ttlcache/v3"
 	"github.com/merlinfuchs/discordgo"
 	"github.com/merlinfuchs/embed-generator/embedg-server/actions/handler"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/helpers"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
 	"github.com/rs/zerolog/log"
 )
 
+// endpointCheckWindow is the time around a PING in which requests with invalid signatures are part of Discord's endpoint check.
+const endpointCheckWindow = 10 * time.Second
+
+const statusWriteTimeout = 5 * time.Second
+
+// recentPings holds the custom bots that have received a valid PING, the failures are checked at the end of
+// the window so PINGs are kept for two windows to cover PINGs that came before the failure.
+var recentPings = ttlcache.New(
+	ttlcache.WithTTL[string, struct{}](2*endpointCheckWindow),
+	ttlcache.WithDisableTouchOnHit[string, struct{}](),
+)
+
+// pendingSignatureFailures holds the custom bots whose signature failure will be recorded at the end of the window.
+var pendingSignatureFailures sync.Map
+
+func init() {
+	go recentPings.Start()
+}
+
 func (h *CustomBotsHandler) HandleCustomBotInteraction(c *fiber.Ctx) error {
 	customBotID := c.Params("customBotID")
 
-	customBot, err := h.pg.Q.GetCustomBot(c.Context(), customBotID)
+	customBot, err := h.pg.GetCustomBot(c.Context(), customBotID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return helpers.NotFound("unknown_bot", "Custom bot not found")
@@ -28,6 +51,11 @@ func (h *CustomBotsHandler) HandleCustomBotInteraction(c *fiber.Ctx) error {
 	}
 
 	if !verifyInteractionSignaure(c, customBot.PublicKey) {
+		// Before the first valid interaction the endpoint is still being set up
+		if customBot.HandledFirstInteraction {
+			h.recordSignatureFailure(customBotID)
+		}
+
 		return helpers.Unauthorized("invalid_signature", "Invalid signature")
 	}
 
@@ -41,17 +69,24 @@ func (h *CustomBotsHandler) HandleCustomBotInteraction(c *fiber.Ctx) error {
 		return fmt.Errorf("application id mismatch")
 	}
 
-	err = h.pg.Q.SetCustomBotHandledFirstInteraction(c.Context(), customBotID)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to set custom bot handled first interaction")
+	if !customBot.HandledFirstInteraction {
+		err = h.pg.Q.SetCustomBotHandledFirstInteraction(c.Context(), customBotID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to set custom bot handled first interaction")
+		}
 	}
 
 	if interaction.Type == discordgo.InteractionPing {
+		recentPings.Set(customBotID, struct{}{}, ttlcache.DefaultTTL)
+
 		return c.JSON(discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponsePong,
 		})
 	}
 
+	// Recording the interaction must not delay the response which is due within 3 seconds
+	go h.recordInteraction(customBotID)
+
 	handle := false
 	switch interaction.Type {
 	case discordgo.InteractionMessageComponent:
@@ -59,7 +94,7 @@ func (h *CustomBotsHandler) HandleCustomBotInteraction(c *fiber.Ctx) error {
 		if strings.HasPrefix(data.CustomID, "action:") {
 			handle = true
 		}
-	case discordgo.InteractionApplicationCommand:
+	case discordgo.InteractionApplicationCommand, discordgo.InteractionApplicationCommandAutocomplete:
 		handle = true
 	}
 
@@ -75,6 +110,14 @@ func (h *CustomBotsHandler) HandleCustomBotInteraction(c *fiber.Ctx) error {
 		go func() {
 			session, _ := discordgo.New("Bot " + customBot.Token)
 
+			if interaction.Type == discordgo.InteractionApplicationCommandAutocomplete {
+				err := h.bot.ActionHandler.HandleAutocompleteInteraction(session, ri)
+				if err != nil {
+					log.Error().Err(err).Msg("Failed to handle autocomplete interaction")
+				}
+				return
+			}
+
 			err := h.bot.ActionHandler.HandleActionInteraction(session, ri)
 			if err != nil {
 				log.Error().Err(err).Msg("Failed to handle action interaction")
@@ -94,6 +137,49 @@ func (h *CustomBotsHandler) HandleCustomBotInteraction(c *fiber.Ctx) error {
 	}
 }
 
+// recordInteraction sets the time of the last interaction that the custom bot has received over the endpoint.
+func (h *CustomBotsHandler) recordInteraction(customBotID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), statusWriteTimeout)
+	defer cancel()
+
+	err := h.pg.Q.SetCustomBotLastInteraction(ctx, pgmodel.SetCustomBotLastInteractionParams{
+		CustomBotID:       customBotID,
+		LastInteractionAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+	})
+	if err != nil {
+		log.Error().Err(err).Str("custom_bot_id", customBotID).Msg("Failed to set custom bot last interaction")
+	}
+}
+
+// recordSignatureFailure sets the time of the last request with an invalid signature once the endpoint check is over.
+// Discord sends requests with invalid signatures together with a valid PING to check the endpoint, failures that
+// happen around a PING are part of that check and aren't recorded. Multiple failures within the window are recorded once.
+func (h *CustomBotsHandler) recordSignatureFailure(customBotID string) {
+	if _, pending := pendingSignatureFailures.LoadOrStore(customBotID, struct{}{}); pending {
+		return
+	}
+
+	failedAt := time.Now().UTC()
+	time.AfterFunc(endpointCheckWindow, func() {
+		defer pendingSignatureFailures.Delete(customBotID)
+
+		if recentPings.Get(customBotID) != nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), statusWriteTimeout)
+		defer cancel()
+
+		err := h.pg.Q.SetCustomBotLastSignatureFailure(ctx, pgmodel.SetCustomBotLastSignatureFailureParams{
+			CustomBotID:            customBotID,
+			LastSignatureFailureAt: sql.NullTime{Time: failedAt, Valid: true},
+		})
+		if err != nil {
+			log.Error().Err(err).Str("custom_bot_id", customBotID).Msg("Failed to set custom bot last signature failure")
+		}
+	})
+}
+
 func verifyInteractionSignaure(c *fiber.Ctx, publicKey string) bool {
 	key, err := hex.DecodeString(publicKey)
 	if err != nil {
@@ -0,0 +1,120 @@
+package custom_bots
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/merlinfuchs/discordgo"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/helpers"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/wire"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v4"
+)
+
+// HandleGetCustomBotStatus reports the health of the gateway session, the interaction endpoint and the deployed commands.
+func (h *CustomBotsHandler) HandleGetCustomBotStatus(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	customBot, err := h.pg.GetCustomBotByGuildID(c.Context(), guildID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return helpers.NotFound("not_configured", "There is no custom bot configured right now")
+		}
+		return err
+	}
+
+	status, err := h.pg.Q.GetCustomBotStatus(c.Context(), customBot.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("Failed to retrieve custom bot status: %w", err)
+	}
+
+	lease, err := h.pg.Q.GetCustomBotLease(c.Context(), customBot.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("Failed to retrieve custom bot lease: %w", err)
+	}
+
+	// The status isn't updated when an instance dies, so it only counts if the instance still holds the lease
+	connected := status.GatewayConnected &&
+		status.InstanceID.Valid &&
+		lease.InstanceID == status.InstanceID.String &&
+		lease.ExpiresAt.After(time.Now().UTC())
+
+	return c.JSON(wire.CustomBotStatusResponseWire{
+		Success: true,
+		Data: wire.CustomBotStatusWire{
+			Gateway: wire.CustomBotGatewayStatusWire{
+				Connected:        connected,
+				TokenValid:       !customBot.TokenInvalid,
+				ReadyAt:          null.Time{NullTime: status.GatewayReadyAt},
+				DisconnectedAt:   null.Time{NullTime: status.GatewayDisconnectedAt},
+				DisconnectReason: null.String{NullString: status.GatewayDisconnectReason},
+				LatencyMs:        null.NewInt(int64(status.GatewayLatencyMs.Int32), connected && status.GatewayLatencyMs.Valid),
+				Reconnects:       int(status.GatewayReconnects),
+				MembersIntent: null.NewBool(
+					discordgo.Intent(status.GatewayIntents.Int32)&discordgo.IntentGuildMembers != 0,
+					status.GatewayIntents.Valid,
+				),
+			},
+			Interactions: wire.CustomBotInteractionsStatusWire{
+				EndpointURL:            interactionEndpointURL(customBot.ID),
+				LastInteractionAt:      null.Time{NullTime: status.LastInteractionAt},
+				LastSignatureFailureAt: null.Time{NullTime: status.LastSignatureFailureAt},
+			},
+			Commands: h.commandsStatus(c.Context(), customBot),
+		},
+	})
+}
+
+// commandsStatus compares the local custom commands with the commands that are deployed to Discord.
+func (h *CustomBotsHandler) commandsStatus(ctx context.Context, customBot pgmodel.CustomBot) wire.CustomBotCommandsStatusWire {
+	res := wire.CustomBotCommandsStatusWire{
+		Missing:  []string{},
+		Outdated: []string{},
+		Unknown:  []string{},
+	}
+
+	commands, err := h.pg.Q.GetCustomCommands(ctx, customBot.GuildID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to retrieve custom commands")
+		res.Error = null.StringFrom("Failed to retrieve the custom commands.")
+		return res
+	}
+
+	collision, local := commandsToPayload(commands)
+	if collision != nil {
+		res.Error = null.StringFrom("There are name collisions in your custom commands, please fix them first.")
+		return res
+	}
+
+	session, err := discordgo.New("Bot " + customBot.Token)
+	if err != nil {
+		res.Error = null.StringFrom("Failed to create custom bot session.")
+		return res
+	}
+
+	remote, err := session.ApplicationCommands(customBot.ApplicationID, customBot.GuildID, discordgo.WithContext(ctx))
+	if err != nil {
+		if derr, ok := err.(*discordgo.RESTError); ok && derr.Response.StatusCode == 401 {
+			res.Error = null.StringFrom("The token of the custom bot is invalid.")
+		} else {
+			log.Error().Err(err).Msg("Failed to retrieve deployed commands of custom bot")
+			res.Error = null.StringFrom("Failed to retrieve the deployed commands from Discord.")
+		}
+		return res
+	}
+
+	diff := diffCommands(local, remote)
+	res.InSync = diff.Empty()
+	res.Missing = commandNames(diff.Create)
+	res.Outdated = commandNames(diff.Update)
+	res.Unknown = commandNames(diff.Delete)
+
+	return res
+}
@@ -0,0 +1,359 @@
+package event_triggers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/merlinfuchs/discordgo"
+	"github.com/merlinfuchs/embed-generator/embedg-server/actions"
+	"github.com/merlinfuchs/embed-generator/embedg-server/actions/parser"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/access"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/helpers"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/session"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/wire"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/merlinfuchs/embed-generator/embedg-server/model"
+	"github.com/merlinfuchs/embed-generator/embedg-server/store"
+	"github.com/merlinfuchs/embed-generator/embedg-server/util"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v4"
+)
+
+type EventTriggerHandler struct {
+	pg           *postgres.PostgresStore
+	am           *access.AccessManager
+	planStore    store.PlanStore
+	actionParser *parser.ActionParser
+}
+
+func New(pg *postgres.PostgresStore, am *access.AccessManager, planStore store.PlanStore, actionParser *parser.ActionParser) *EventTriggerHandler {
+	return &EventTriggerHandler{
+		pg:           pg,
+		am:           am,
+		planStore:    planStore,
+		actionParser: actionParser,
+	}
+}
+
+func (h *EventTriggerHandler) HandleListEventTriggers(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	triggers, err := h.pg.Q.GetEventTriggers(c.Context(), guildID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get event triggers")
+		return err
+	}
+
+	res := make([]wire.EventTriggerWire, len(triggers))
+	for i, trigger := range triggers {
+		res[i] = eventTriggerModelToWire(trigger)
+	}
+
+	return c.JSON(wire.EventTriggerListResponseWire{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *EventTriggerHandler) HandleGetEventTrigger(c *fiber.Ctx) error {
+	triggerID := c.Params("triggerID")
+	guildID := c.Query("guild_id")
+
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	trigger, err := h.pg.Q.GetEventTrigger(c.Context(), pgmodel.GetEventTriggerParams{
+		ID:      triggerID,
+		GuildID: guildID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return helpers.NotFound("unknown_trigger", "The event trigger does not exist.")
+		}
+		log.Error().Err(err).Msg("Failed to get event trigger")
+		return err
+	}
+
+	return c.JSON(wire.EventTriggerGetResponseWire{
+		Success: true,
+		Data:    eventTriggerModelToWire(trigger),
+	})
+}
+
+func (h *EventTriggerHandler) HandleCreateEventTrigger(c *fiber.Ctx, req wire.EventTriggerCreateRequestWire) error {
+	session := c.Locals("session").(*session.Session)
+	guildID := c.Query("guild_id")
+
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	if err := h.checkCustomBot(c, guildID); err != nil {
+		return err
+	}
+
+	features, err := h.planStore.GetPlanFeaturesForGuild(c.Context(), guildID)
+	if err != nil {
+		return err
+	}
+
+	existingCount, err := h.pg.Q.CountEventTriggers(c.Context(), guildID)
+	if err != nil {
+		return err
+	}
+
+	if int(existingCount) >= features.MaxEventTriggers {
+		return helpers.Forbidden("insufficient_plan", "You have reached the maximum number of event triggers for your plan!")
+	}
+
+	rawActions, rawDerivedPerms, err := h.checkEventTrigger(c, session.UserID, guildID, req.Event, req.SavedMessageID, req.ChannelID, req.Actions)
+	if err != nil {
+		return err
+	}
+
+	trigger, err := h.pg.Q.InsertEventTrigger(c.Context(), pgmodel.InsertEventTriggerParams{
+		ID:        util.UniqueID(),
+		CreatorID: session.UserID,
+		GuildID:   guildID,
+		Event:     req.Event,
+		Name:      req.Name,
+		SavedMessageID: sql.NullString{
+			String: req.SavedMessageID.String,
+			Valid:  req.SavedMessageID.Valid,
+		},
+		ChannelID: sql.NullString{
+			String: req.ChannelID.String,
+			Valid:  req.ChannelID.Valid,
+		},
+		Actions:            rawActions,
+		DerivedPermissions: rawDerivedPerms,
+		Enabled:            req.Enabled,
+		CreatedAt:          time.Now().UTC(),
+		UpdatedAt:          time.Now().UTC(),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create event trigger")
+		return err
+	}
+
+	return c.JSON(wire.EventTriggerCreateResponseWire{
+		Success: true,
+		Data:    eventTriggerModelToWire(trigger),
+	})
+}
+
+func (h *EventTriggerHandler) HandleUpdateEventTrigger(c *fiber.Ctx, req wire.EventTriggerUpdateRequestWire) error {
+	session := c.Locals("session").(*session.Session)
+	triggerID := c.Params("triggerID")
+	guildID := c.Query("guild_id")
+
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	if req.Enabled {
+		if err := h.checkCustomBot(c, guildID); err != nil {
+			return err
+		}
+	}
+
+	rawActions, rawDerivedPerms, err := h.checkEventTrigger(c, session.UserID, guildID, req.Event, req.SavedMessageID, req.ChannelID, req.Actions)
+	if err != nil {
+		return err
+	}
+
+	trigger, err := h.pg.Q.UpdateEventTrigger(c.Context(), pgmodel.UpdateEventTriggerParams{
+		ID:      triggerID,
+		GuildID: guildID,
+		Event:   req.Event,
+		Name:    req.Name,
+		SavedMessageID: sql.NullString{
+			String: req.SavedMessageID.String,
+			Valid:  req.SavedMessageID.Valid,
+		},
+		ChannelID: sql.NullString{
+			String: req.ChannelID.String,
+			Valid:  req.ChannelID.Valid,
+		},
+		Actions:            rawActions,
+		DerivedPermissions: rawDerivedPerms,
+		Enabled:            req.Enabled,
+		UpdatedAt:          time.Now().UTC(),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return helpers.NotFound("unknown_trigger", "The event trigger does not exist.")
+		}
+		log.Error().Err(err).Msg("Failed to update event trigger")
+		return err
+	}
+
+	return c.JSON(wire.EventTriggerUpdateResponseWire{
+		Success: true,
+		Data:    eventTriggerModelToWire(trigger),
+	})
+}
+
+func (h *EventTriggerHandler) HandleDeleteEventTrigger(c *fiber.Ctx) error {
+	triggerID := c.Params("triggerID")
+	guildID := c.Query("guild_id")
+
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	_, err := h.pg.Q.DeleteEventTrigger(c.Context(), pgmodel.DeleteEventTriggerParams{
+		ID:      triggerID,
+		GuildID: guildID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return helpers.NotFound("unknown_trigger", "The event trigger does not exist.")
+		}
+		log.Error().Err(err).Msg("Failed to delete event trigger")
+		return err
+	}
+
+	return c.JSON(wire.EventTriggerDeleteResponseWire{
+		Success: true,
+		Data:    struct{}{},
+	})
+}
+
+// checkCustomBot makes sure that the custom bot of the guild can run event triggers.
+// Only custom bots receive member events, and only if the Server Members Intent is enabled for them.
+func (h *EventTriggerHandler) checkCustomBot(c *fiber.Ctx, guildID string) error {
+	customBot, err := h.pg.Q.GetCustomBotByGuildID(c.Context(), guildID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return helpers.BadRequest("custom_bot_required", "Event triggers are run by your custom bot, please configure it first.")
+		}
+		return err
+	}
+
+	if customBot.TokenInvalid {
+		return helpers.BadRequest("custom_bot_required", "The token of your custom bot is invalid, please update it first.")
+	}
+
+	status, err := h.pg.Q.GetCustomBotStatus(c.Context(), customBot.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	// The intents are only known once the custom bot has connected
+	if status.GatewayIntents.Valid && discordgo.Intent(status.GatewayIntents.Int32)&discordgo.IntentGuildMembers == 0 {
+		return helpers.BadRequest(
+			"missing_members_intent",
+			"Your custom bot doesn't receive member events, enable the Server Members Intent in the Discord Developer Portal and save your custom bot settings again.",
+		)
+	}
+
+	return nil
+}
+
+// checkEventTrigger makes sure that the user is allowed to create the trigger and returns the normalized actions
+// together with the permissions of the user that the actions are executed with.
+func (h *EventTriggerHandler) checkEventTrigger(
+	c *fiber.Ctx,
+	userID string,
+	guildID string,
+	event string,
+	savedMessageID null.String,
+	channelID null.String,
+	rawActions []byte,
+) (json.RawMessage, json.RawMessage, error) {
+	if channelID.Valid {
+		if err := h.am.CheckChannelAccessForRequest(c, channelID.String); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if savedMessageID.Valid {
+		_, err := h.pg.Q.GetSavedMessageForGuild(c.Context(), pgmodel.GetSavedMessageForGuildParams{
+			ID:      savedMessageID.String,
+			GuildID: sql.NullString{String: guildID, Valid: true},
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, nil, helpers.NotFound("unknown_message", "The saved message does not exist or belongs to a different server.")
+			}
+			return nil, nil, err
+		}
+	}
+
+	actionSet := actions.ActionSet{}
+	if len(rawActions) != 0 {
+		if err := json.Unmarshal(rawActions, &actionSet); err != nil {
+			return nil, nil, helpers.BadRequest("invalid_actions", "The actions are invalid.")
+		}
+	}
+
+	if !savedMessageID.Valid && len(actionSet.Actions) == 0 {
+		return nil, nil, helpers.BadRequest("invalid_trigger", "The event trigger must send a message or have at least one action.")
+	}
+
+	if len(actionSet.Actions) > 5 {
+		return nil, nil, helpers.BadRequest("invalid_actions", "An event trigger can't have more than 5 actions.")
+	}
+
+	for _, action := range actionSet.Actions {
+		switch action.Type {
+		case actions.ActionTypeAddRole, actions.ActionTypeRemoveRole, actions.ActionTypeToggleRole:
+			if event == model.EventTriggerMemberLeave {
+				return nil, nil, helpers.BadRequest("invalid_actions", "Roles can't be changed after a member has left the server.")
+			}
+		case actions.ActionTypeTextDM, actions.ActionTypeSavedMessageDM:
+			if event == model.EventTriggerMemberLeave {
+				return nil, nil, helpers.BadRequest("invalid_actions", "Members can't be sent a DM after they have left the server.")
+			}
+		default:
+			return nil, nil, helpers.BadRequest("invalid_actions", "Event triggers only support actions that change roles or send DMs.")
+		}
+	}
+
+	err := h.actionParser.CheckPermissionsForActionSets(map[string]actions.ActionSet{"": actionSet}, userID, guildID, channelID.String)
+	if err != nil {
+		return nil, nil, helpers.BadRequest("invalid_actions", err.Error())
+	}
+
+	derivedPerms, err := h.actionParser.DerivePermissionsForActions(userID, guildID, channelID.String)
+	if err != nil {
+		return nil, nil, helpers.BadRequest("invalid_actions", err.Error())
+	}
+
+	normalizedActions, err := json.Marshal(actionSet)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rawDerivedPerms, err := json.Marshal(derivedPerms)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return normalizedActions, rawDerivedPerms, nil
+}
+
+func eventTriggerModelToWire(trigger pgmodel.EventTrigger) wire.EventTriggerWire {
+	return wire.EventTriggerWire{
+		ID:             trigger.ID,
+		CreatorID:      trigger.CreatorID,
+		GuildID:        trigger.GuildID,
+		Event:          trigger.Event,
+		Name:           trigger.Name,
+		SavedMessageID: null.NewString(trigger.SavedMessageID.String, trigger.SavedMessageID.Valid),
+		ChannelID:      null.NewString(trigger.ChannelID.String, trigger.ChannelID.Valid),
+		Actions:        trigger.Actions,
+		Enabled:        trigger.Enabled,
+		CreatedAt:      trigger.CreatedAt,
+		UpdatedAt:      trigger.UpdatedAt,
+	}
+}
@@ -268,7 +268,7 @@ func (h *GuildsHanlder) HandleGetGuildBranding(c *fiber.Ctx) error {
 
 	res := wire.GuildBrandingWire{}
 
-	customBot, err := h.pg.Q.GetCustomBotByGuildID(c.Context(), guildID)
+	customBot, err := h.pg.GetCustomBotByGuildID(c.Context(), guildID)
 	if err != nil {
 		if err != sql.ErrNoRows {
 			return err
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/merlinfuchs/embed-generator/embedg-server/actions/template"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/wire"
 	"github.com/merlinfuchs/embed-generator/embedg-server/bot"
 )
@@ -80,3 +81,15 @@ func (h *HealthHandler) HandleHealthShardList(c *fiber.Ctx) error {
 		Shards:     shardListWire,
 	})
 }
+
+func (h *HealthHandler) HandleHealthTemplateCache(c *fiber.Ctx) error {
+	stats := template.GetCompiledCacheStats()
+
+	return c.JSON(wire.TemplateCacheStatsWire{
+		Size:      stats.Size,
+		Hits:      stats.Hits,
+		Misses:    stats.Misses,
+		Evictions: stats.Evictions,
+		HitRate:   stats.HitRate,
+	})
+}
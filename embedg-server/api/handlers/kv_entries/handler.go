@@ -0,0 +1,314 @@
+package kv_entries
+
+import (
+	"math"
+	"net/url"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/access"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/helpers"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/wire"
+	"github.com/merlinfuchs/embed-generator/embedg-server/model"
+	"github.com/merlinfuchs/embed-generator/embedg-server/store"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultListLimit = 100
+const maxListLimit = 1000
+
+type KVEntriesHandler struct {
+	kvStore   store.KVEntryStore
+	am        *access.AccessManager
+	planStore store.PlanStore
+}
+
+func New(kvStore store.KVEntryStore, am *access.AccessManager, planStore store.PlanStore) *KVEntriesHandler {
+	return &KVEntriesHandler{
+		kvStore:   kvStore,
+		am:        am,
+		planStore: planStore,
+	}
+}
+
+func (h *KVEntriesHandler) HandleListKVEntries(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	pattern := c.Query("pattern", "%")
+
+	limit, offset, err := pageParams(c)
+	if err != nil {
+		return err
+	}
+
+	entries, total, err := h.kvStore.ListKVEntries(c.Context(), guildID, pattern, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list KV entries")
+		return err
+	}
+
+	res := make([]wire.KVEntryWire, len(entries))
+	for i, entry := range entries {
+		res[i] = kvEntryModelToWire(entry)
+	}
+
+	return c.JSON(wire.KVEntryListResponseWire{
+		Success: true,
+		Data: wire.KVEntryListResponseDataWire{
+			Entries: res,
+			Total:   total,
+		},
+	})
+}
+
+func (h *KVEntriesHandler) HandleGetKVEntry(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	key, err := keyParam(c)
+	if err != nil {
+		return err
+	}
+
+	entry, err := h.kvStore.GetKVEntry(c.Context(), guildID, key)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return helpers.NotFound("unknown_key", "The key does not exist.")
+		}
+		log.Error().Err(err).Msg("Failed to get KV entry")
+		return err
+	}
+
+	return c.JSON(wire.KVEntryGetResponseWire{
+		Success: true,
+		Data:    kvEntryModelToWire(entry),
+	})
+}
+
+func (h *KVEntriesHandler) HandleUpdateKVEntry(c *fiber.Ctx, req wire.KVEntryUpdateRequestWire) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	key, err := keyParam(c)
+	if err != nil {
+		return err
+	}
+
+	if req.ExpiresAt.Valid && !req.ExpiresAt.Time.After(time.Now().UTC()) {
+		return helpers.BadRequest("invalid_expires_at", "The expires_at field must be in the future.")
+	}
+
+	existing, err := h.kvStore.GetKVEntry(c.Context(), guildID, key)
+	if err != nil {
+		if err != store.ErrNotFound {
+			log.Error().Err(err).Msg("Failed to get KV entry")
+			return err
+		}
+
+		if err := h.checkKeyCountLimit(c, guildID, 1); err != nil {
+			return err
+		}
+		existing.CreatedAt = time.Now().UTC()
+	}
+
+	entry := model.KVEntry{
+		Key:       key,
+		GuildID:   guildID,
+		Value:     req.Value,
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: existing.CreatedAt,
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if err := h.kvStore.SetKVEntry(c.Context(), entry); err != nil {
+		log.Error().Err(err).Msg("Failed to set KV entry")
+		return err
+	}
+
+	return c.JSON(wire.KVEntryUpdateResponseWire{
+		Success: true,
+		Data:    kvEntryModelToWire(entry),
+	})
+}
+
+func (h *KVEntriesHandler) HandleDeleteKVEntry(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	key, err := keyParam(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.kvStore.DeleteKVEntry(c.Context(), guildID, key)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return helpers.NotFound("unknown_key", "The key does not exist.")
+		}
+		log.Error().Err(err).Msg("Failed to delete KV entry")
+		return err
+	}
+
+	return c.JSON(wire.KVEntryDeleteResponseWire{
+		Success: true,
+		Data:    struct{}{},
+	})
+}
+
+func (h *KVEntriesHandler) HandleImportKVEntries(c *fiber.Ctx, req wire.KVEntriesImportRequestWire) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	features, err := h.planStore.GetPlanFeaturesForGuild(c.Context(), guildID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	// When a key is contained multiple times the last entry wins
+	entries := make([]model.KVEntry, 0, len(req.Entries))
+	indexes := make(map[string]int, len(req.Entries))
+	for _, entry := range req.Entries {
+		if entry.ExpiresAt.Valid && !entry.ExpiresAt.Time.After(now) {
+			return helpers.BadRequest("invalid_expires_at", "The expires_at field of "+entry.Key+" must be in the future.")
+		}
+
+		e := model.KVEntry{
+			Key:       entry.Key,
+			GuildID:   guildID,
+			Value:     entry.Value,
+			ExpiresAt: entry.ExpiresAt,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		if i, ok := indexes[entry.Key]; ok {
+			entries[i] = e
+		} else {
+			indexes[entry.Key] = len(entries)
+			entries = append(entries, e)
+		}
+	}
+
+	created, err := h.kvStore.ImportKVEntries(c.Context(), guildID, entries, features.MaxKVKeys)
+	if err != nil {
+		if err == store.ErrTooManyKeys {
+			return helpers.Forbidden("insufficient_plan", "You have reached the maximum number of keys for your plan.")
+		}
+		if err == store.ErrValueTooLarge {
+			return helpers.BadRequest("value_too_large", "One of the values is too large.")
+		}
+		log.Error().Err(err).Msg("Failed to import KV entries")
+		return err
+	}
+
+	return c.JSON(wire.KVEntriesImportResponseWire{
+		Success: true,
+		Data: wire.KVEntriesImportResponseDataWire{
+			CreatedCount: created,
+			UpdatedCount: len(entries) - created,
+		},
+	})
+}
+
+// HandleExportKVEntries returns a page of the entries of the guild in the format of the import.
+func (h *KVEntriesHandler) HandleExportKVEntries(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	limit, offset, err := pageParams(c)
+	if err != nil {
+		return err
+	}
+
+	entries, total, err := h.kvStore.ListKVEntries(c.Context(), guildID, "%", limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list KV entries")
+		return err
+	}
+
+	res := make([]wire.KVEntryImportWire, len(entries))
+	for i, entry := range entries {
+		res[i] = wire.KVEntryImportWire{
+			Key:       entry.Key,
+			Value:     entry.Value,
+			ExpiresAt: entry.ExpiresAt,
+		}
+	}
+
+	return c.JSON(wire.KVEntriesExportResponseWire{
+		Success: true,
+		Data: wire.KVEntriesExportResponseDataWire{
+			Entries: res,
+			Total:   total,
+		},
+	})
+}
+
+// pageParams returns the limit and offset from the query, the maximum limit matches the maximum number of entries per import.
+func pageParams(c *fiber.Ctx) (int, int, error) {
+	limit := c.QueryInt("limit", defaultListLimit)
+	if limit <= 0 || limit > maxListLimit {
+		return 0, 0, helpers.BadRequest("invalid_limit", "The limit must be between 1 and 1000.")
+	}
+	offset := c.QueryInt("offset", 0)
+	if offset < 0 || offset > math.MaxInt32 {
+		return 0, 0, helpers.BadRequest("invalid_offset", "The offset must not be negative or too large.")
+	}
+	return limit, offset, nil
+}
+
+func (h *KVEntriesHandler) checkKeyCountLimit(c *fiber.Ctx, guildID string, newKeys int) error {
+	if newKeys == 0 {
+		return nil
+	}
+
+	features, err := h.planStore.GetPlanFeaturesForGuild(c.Context(), guildID)
+	if err != nil {
+		return err
+	}
+
+	count, err := h.kvStore.CountKVEntries(c.Context(), guildID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to count KV entries")
+		return err
+	}
+
+	if count+newKeys > features.MaxKVKeys {
+		return helpers.Forbidden("insufficient_plan", "You have reached the maximum number of keys for your plan.")
+	}
+
+	return nil
+}
+
+func keyParam(c *fiber.Ctx) (string, error) {
+	key, err := url.PathUnescape(c.Params("key"))
+	if err != nil || key == "" || len(key) > 256 {
+		return "", helpers.BadRequest("invalid_key", "The key is invalid.")
+	}
+	return key, nil
+}
+
+func kvEntryModelToWire(model model.KVEntry) wire.KVEntryWire {
+	return wire.KVEntryWire{
+		Key:       model.Key,
+		Value:     model.Value,
+		ExpiresAt: model.ExpiresAt,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
@@ -71,7 +71,11 @@ func (h *PremiumHandler) HandleGetFeatures(c *fiber.Ctx) error {
 			IsPremium:                 features.IsPremium,
 			MaxImageUploadSize:        features.MaxImageUploadSize,
 			MaxScheduledMessages:      features.MaxScheduledMessages,
+			MaxEventTriggers:          features.MaxEventTriggers,
 			PeriodicScheduledMessages: features.PeriodicScheduledMessages,
+			MaxTemplateOps:            features.MaxTemplateOps,
+			MaxKVKeys:                 features.MaxKVKeys,
+			HTTPRequests:              features.HTTPRequests,
 		},
 	})
 }
@@ -211,6 +211,104 @@ func (h *SavedMessagesHandler) HandleImportSavedMessages(c *fiber.Ctx, req wire.
 	})
 }
 
+// Variants are only used by actions which can only reference saved messages of the guild.
+func (h *SavedMessagesHandler) getGuildSavedMessage(c *fiber.Ctx) (pgmodel.SavedMessage, error) {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return pgmodel.SavedMessage{}, err
+	}
+
+	message, err := h.pg.Q.GetSavedMessageForGuild(c.Context(), pgmodel.GetSavedMessageForGuildParams{
+		ID:      c.Params("messageID"),
+		GuildID: sql.NullString{String: guildID, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return pgmodel.SavedMessage{}, helpers.NotFound("unknown_message", "The message does not exist.")
+		}
+		log.Error().Err(err).Msg("Failed to get saved message")
+		return pgmodel.SavedMessage{}, err
+	}
+
+	return message, nil
+}
+
+func (h *SavedMessagesHandler) HandleListSavedMessageVariants(c *fiber.Ctx) error {
+	message, err := h.getGuildSavedMessage(c)
+	if err != nil {
+		return err
+	}
+
+	variants, err := h.pg.Q.GetSavedMessageVariants(c.Context(), message.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get saved message variants")
+		return err
+	}
+
+	res := make([]wire.SavedMessageVariantWire, len(variants))
+	for i, variant := range variants {
+		res[i] = savedMessageVariantModelToWire(variant)
+	}
+
+	return c.JSON(wire.SavedMessageVariantListResponseWire{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *SavedMessagesHandler) HandleUpdateSavedMessageVariant(c *fiber.Ctx, req wire.SavedMessageVariantUpdateRequestWire) error {
+	locale := c.Params("locale")
+	if !wire.IsValidLocale(locale) {
+		return helpers.BadRequest("invalid_locale", "The locale is invalid.")
+	}
+
+	message, err := h.getGuildSavedMessage(c)
+	if err != nil {
+		return err
+	}
+
+	variant, err := h.pg.Q.UpsertSavedMessageVariant(c.Context(), pgmodel.UpsertSavedMessageVariantParams{
+		SavedMessageID: message.ID,
+		Locale:         locale,
+		Data:           req.Data,
+		UpdatedAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update saved message variant")
+		return err
+	}
+
+	return c.JSON(wire.SavedMessageVariantUpdateResponseWire{
+		Success: true,
+		Data:    savedMessageVariantModelToWire(variant),
+	})
+}
+
+func (h *SavedMessagesHandler) HandleDeleteSavedMessageVariant(c *fiber.Ctx) error {
+	message, err := h.getGuildSavedMessage(c)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := h.pg.Q.DeleteSavedMessageVariant(c.Context(), pgmodel.DeleteSavedMessageVariantParams{
+		SavedMessageID: message.ID,
+		Locale:         c.Params("locale"),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete saved message variant")
+		return err
+	}
+
+	if deleted == 0 {
+		return helpers.NotFound("unknown_variant", "The message has no variant for this locale.")
+	}
+
+	return c.JSON(wire.SavedMessageVariantDeleteResponseWire{
+		Success: true,
+		Data:    struct{}{},
+	})
+}
+
 func savedMessageModelToWire(model pgmodel.SavedMessage) wire.SavedMessageWire {
 	return wire.SavedMessageWire{
 		ID:          model.ID,
@@ -222,3 +320,11 @@ func savedMessageModelToWire(model pgmodel.SavedMessage) wire.SavedMessageWire {
 		Data:        model.Data,
 	}
 }
+
+func savedMessageVariantModelToWire(model pgmodel.SavedMessageVariant) wire.SavedMessageVariantWire {
+	return wire.SavedMessageVariantWire{
+		Locale:    model.Locale,
+		Data:      model.Data,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
@@ -2,6 +2,7 @@ package send_message
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -63,11 +64,18 @@ func (h *SendMessageHandler) HandleSendMessageToChannel(c *fiber.Ctx, req wire.M
 		return fmt.Errorf("could not get plan features: %w", err)
 	}
 
+	templateCtx, cancel := context.WithTimeout(c.Context(), template.DefaultTimeout)
+	defer cancel()
+
 	templates := template.NewContext(
-		"SEND_MESSAGE", features.MaxTemplateOps,
+		templateCtx, "SEND_MESSAGE", features.MaxTemplateOps,
 		template.NewGuildProvider(h.bot.State, channel.GuildID, nil),
 		template.NewChannelProvider(h.bot.State, req.ChannelID, nil),
 		template.NewKVProvider(channel.GuildID, h.pg, features.MaxKVKeys),
+		template.NewEntityProvider(h.bot.State, h.bot.Rest, channel.GuildID),
+		template.NewTranslationProvider(channel.GuildID, h.pg, template.GuildLocale(h.bot.State, channel.GuildID)),
+		template.NewSnippetProvider(channel.GuildID, h.pg),
+		template.NewHTTPProvider(channel.GuildID, h.pg, nil, features.HTTPRequests),
 	)
 
 	data := &actions.MessageWithActions{}
@@ -140,7 +148,7 @@ func (h *SendMessageHandler) HandleSendMessageToChannel(c *fiber.Ctx, req wire.M
 		return fmt.Errorf("Failed to create permission context: %w", err)
 	}
 
-	err = h.actionParser.CreateActionsForMessage(c.Context(), data.Actions, permContext, msg.ID, false)
+	err = h.actionParser.CreateActionsForMessage(c.Context(), data.Actions, permContext, req.GuildID, msg.ID, false)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to create actions for message")
 		return err
@@ -0,0 +1,236 @@
+package templates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/merlinfuchs/discordgo"
+	"github.com/merlinfuchs/embed-generator/embedg-server/actions"
+	"github.com/merlinfuchs/embed-generator/embedg-server/actions/template"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/access"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/helpers"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/session"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/wire"
+	"github.com/merlinfuchs/embed-generator/embedg-server/bot"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres"
+	"github.com/merlinfuchs/embed-generator/embedg-server/kv_entries"
+	"github.com/merlinfuchs/embed-generator/embedg-server/store"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/guregu/null.v4"
+)
+
+type TemplatesHandler struct {
+	pg        *postgres.PostgresStore
+	bot       *bot.Bot
+	am        *access.AccessManager
+	planStore store.PlanStore
+}
+
+func New(pg *postgres.PostgresStore, bot *bot.Bot, am *access.AccessManager, planStore store.PlanStore) *TemplatesHandler {
+	return &TemplatesHandler{
+		pg:        pg,
+		bot:       bot,
+		am:        am,
+		planStore: planStore,
+	}
+}
+
+func (h *TemplatesHandler) HandleLintTemplates(c *fiber.Ctx, req wire.TemplateLintRequestWire) error {
+	data := &actions.MessageWithActions{}
+	if err := json.Unmarshal(req.Data, data); err != nil {
+		return helpers.BadRequest("invalid_message", "The message data is invalid.")
+	}
+
+	diagnostics := h.lintContext(c.Context()).LintMessage(data)
+
+	res := make([]wire.TemplateDiagnosticWire, len(diagnostics))
+	for i, diagnostic := range diagnostics {
+		res[i] = wire.TemplateDiagnosticWire{
+			Path:        diagnostic.Path,
+			Line:        diagnostic.Line,
+			Column:      diagnostic.Column,
+			Message:     diagnostic.Message,
+			Suggestions: diagnostic.Suggestions,
+		}
+	}
+
+	return c.JSON(wire.TemplateLintResponseWire{
+		Success: true,
+		Data: wire.TemplateLintResponseDataWire{
+			Valid:       len(diagnostics) == 0,
+			Diagnostics: res,
+		},
+	})
+}
+
+// lintContext creates a context that is only used to parse templates, the providers are only needed for the function map.
+func (h *TemplatesHandler) lintContext(ctx context.Context) *template.TemplateContext {
+	return template.NewContext(
+		ctx, "LINT", 0,
+		template.NewGuildProvider(h.bot.State, "", nil),
+		template.NewChannelProvider(h.bot.State, "", nil),
+		template.NewKVProvider("", h.pg, 0),
+		template.NewEntityProvider(h.bot.State, h.bot.Rest, ""),
+		template.NewTranslationProvider("", h.pg),
+		template.NewSnippetProvider("", h.pg),
+		template.NewHTTPProvider("", h.pg, nil, false),
+	)
+}
+
+func (h *TemplatesHandler) HandleRenderTemplatePreview(c *fiber.Ctx, req wire.TemplatePreviewRequestWire) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	channelID := req.ChannelID.String
+	if channelID != "" {
+		if err := h.am.CheckChannelAccessForRequest(c, channelID); err != nil {
+			return err
+		}
+
+		channel, err := h.bot.State.Channel(channelID)
+		if err != nil || channel.GuildID != guildID {
+			return helpers.BadRequest("invalid_channel", "The channel doesn't belong to the guild.")
+		}
+	}
+
+	features, err := h.planStore.GetPlanFeaturesForGuild(c.Context(), guildID)
+	if err != nil {
+		return fmt.Errorf("could not get plan features: %w", err)
+	}
+
+	data := &actions.MessageWithActions{}
+	if err := json.Unmarshal(req.Data, data); err != nil {
+		return helpers.BadRequest("invalid_message", "The message data is invalid.")
+	}
+
+	interaction, err := h.previewInteraction(c, guildID, channelID, req)
+	if err != nil {
+		return err
+	}
+
+	// Writes to the KV store only go to the overlay so previews never change the data of the guild
+	kvStore := kv_entries.NewOverlayStore(h.pg, guildID, req.KV)
+
+	templateCtx, cancel := context.WithTimeout(c.Context(), template.DefaultTimeout)
+	defer cancel()
+
+	templates := template.NewContext(
+		templateCtx, "PREVIEW", features.MaxTemplateOps,
+		template.NewInteractionProvider(h.bot.State, interaction),
+		template.NewKVProvider(guildID, kvStore, features.MaxKVKeys),
+		template.NewEntityProvider(h.bot.State, h.bot.Rest, guildID),
+		template.NewTranslationProvider(guildID, h.pg, string(interaction.Locale), template.GuildLocale(h.bot.State, guildID)),
+		template.NewSnippetProvider(guildID, h.pg),
+		// Previews can be run by anyone with access to the guild, so they must not send requests to the hosts of the guild
+		template.NewHTTPProvider(guildID, h.pg, nil, features.HTTPRequests).WithCacheOnly(),
+	)
+
+	if err := templates.ParseAndExecuteMessage(data); err != nil {
+		return c.JSON(wire.TemplatePreviewResponseWire{
+			Success: true,
+			Data: wire.TemplatePreviewResponseDataWire{
+				Error: null.StringFrom(err.Error()),
+			},
+		})
+	}
+
+	rendered, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(wire.TemplatePreviewResponseWire{
+		Success: true,
+		Data: wire.TemplatePreviewResponseDataWire{
+			Data: rendered,
+		},
+	})
+}
+
+// previewInteraction builds a fake interaction from the mock data of the request which defaults to the current user.
+func (h *TemplatesHandler) previewInteraction(c *fiber.Ctx, guildID string, channelID string, req wire.TemplatePreviewRequestWire) (*discordgo.Interaction, error) {
+	member := &discordgo.Member{
+		GuildID: guildID,
+		Roles:   []string{},
+	}
+
+	if req.User != nil {
+		member.User = &discordgo.User{
+			ID:         req.User.ID,
+			Username:   req.User.Username,
+			GlobalName: req.User.GlobalName.String,
+			Avatar:     req.User.Avatar.String,
+		}
+		member.Nick = req.User.Nick.String
+		if req.User.Roles != nil {
+			member.Roles = req.User.Roles
+		}
+	} else {
+		session := c.Locals("session").(*session.Session)
+
+		user, err := h.pg.Q.GetUser(c.Context(), session.UserID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to get user")
+			return nil, err
+		}
+
+		member.User = &discordgo.User{
+			ID:            user.ID,
+			Username:      user.Name,
+			Discriminator: user.Discriminator,
+			Avatar:        user.Avatar.String,
+		}
+	}
+
+	// Without a mock command the preview has neither command nor component data, a modal submit
+	// makes .Interaction.Command and .Interaction.Component return nil instead of failing.
+	interaction := &discordgo.Interaction{
+		Type:      discordgo.InteractionModalSubmit,
+		Data:      discordgo.ModalSubmitInteractionData{},
+		GuildID:   guildID,
+		ChannelID: channelID,
+		Member:    member,
+		Locale:    discordgo.Locale(req.Locale.String),
+	}
+
+	if req.Command != nil {
+		data := discordgo.ApplicationCommandInteractionData{
+			Name:     req.Command.Name,
+			Resolved: &discordgo.ApplicationCommandInteractionDataResolved{},
+		}
+
+		for name, value := range req.Command.Options {
+			option := &discordgo.ApplicationCommandInteractionDataOption{
+				Name:  name,
+				Value: value,
+			}
+
+			switch v := value.(type) {
+			case string:
+				option.Type = discordgo.ApplicationCommandOptionString
+			case bool:
+				option.Type = discordgo.ApplicationCommandOptionBoolean
+			case float64:
+				if v == math.Trunc(v) {
+					option.Type = discordgo.ApplicationCommandOptionInteger
+				} else {
+					option.Type = discordgo.ApplicationCommandOptionNumber
+				}
+			default:
+				return nil, helpers.BadRequest("invalid_command_option", fmt.Sprintf("The value of the option %s must be a string, number or boolean.", name))
+			}
+
+			data.Options = append(data.Options, option)
+		}
+
+		interaction.Type = discordgo.InteractionApplicationCommand
+		interaction.Data = data
+	}
+
+	return interaction, nil
+}
@@ -0,0 +1,120 @@
+package templates
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/helpers"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/wire"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/rs/zerolog/log"
+)
+
+const maxGuildHTTPHosts = 25
+
+func (h *TemplatesHandler) HandleListTemplateHTTPHosts(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	hosts, err := h.pg.Q.GetTemplateHTTPHosts(c.Context(), guildID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get template HTTP hosts")
+		return err
+	}
+
+	res := make([]wire.TemplateHTTPHostWire, len(hosts))
+	for i, host := range hosts {
+		res[i] = templateHTTPHostModelToWire(host)
+	}
+
+	return c.JSON(wire.TemplateHTTPHostListResponseWire{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *TemplatesHandler) HandleCreateTemplateHTTPHost(c *fiber.Ctx, req wire.TemplateHTTPHostCreateRequestWire) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	features, err := h.planStore.GetPlanFeaturesForGuild(c.Context(), guildID)
+	if err != nil {
+		return fmt.Errorf("could not get plan features: %w", err)
+	}
+
+	if !features.HTTPRequests {
+		return helpers.Forbidden("insufficient_plan", "This feature is not available on your plan!")
+	}
+
+	host := strings.ToLower(strings.TrimSpace(req.Host))
+	if !wire.IsValidHTTPHost(host) {
+		return helpers.BadRequest("invalid_host", "The host must be a domain name like example.com or *.example.com.")
+	}
+
+	hosts, err := h.pg.Q.GetTemplateHTTPHosts(c.Context(), guildID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get template HTTP hosts")
+		return err
+	}
+
+	exists := slices.ContainsFunc(hosts, func(existing pgmodel.TemplateHttpHost) bool {
+		return existing.Host == host
+	})
+	if !exists && len(hosts) >= maxGuildHTTPHosts {
+		return helpers.BadRequest("too_many_hosts", fmt.Sprintf("A server can't have more than %d allowed hosts.", maxGuildHTTPHosts))
+	}
+
+	row, err := h.pg.Q.UpsertTemplateHTTPHost(c.Context(), pgmodel.UpsertTemplateHTTPHostParams{
+		GuildID:   guildID,
+		Host:      host,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create template HTTP host")
+		return err
+	}
+
+	return c.JSON(wire.TemplateHTTPHostCreateResponseWire{
+		Success: true,
+		Data:    templateHTTPHostModelToWire(row),
+	})
+}
+
+func (h *TemplatesHandler) HandleDeleteTemplateHTTPHost(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	deleted, err := h.pg.Q.DeleteTemplateHTTPHost(c.Context(), pgmodel.DeleteTemplateHTTPHostParams{
+		GuildID: guildID,
+		Host:    strings.ToLower(c.Params("host")),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete template HTTP host")
+		return err
+	}
+
+	if deleted == 0 {
+		return helpers.NotFound("unknown_host", "The host is not on the allowlist.")
+	}
+
+	return c.JSON(wire.TemplateHTTPHostDeleteResponseWire{
+		Success: true,
+		Data:    struct{}{},
+	})
+}
+
+func templateHTTPHostModelToWire(model pgmodel.TemplateHttpHost) wire.TemplateHTTPHostWire {
+	return wire.TemplateHTTPHostWire{
+		Host:      model.Host,
+		CreatedAt: model.CreatedAt,
+	}
+}
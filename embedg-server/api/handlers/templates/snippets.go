@@ -0,0 +1,124 @@
+package templates
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/helpers"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/wire"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/rs/zerolog/log"
+)
+
+const maxGuildSnippets = 100
+
+func (h *TemplatesHandler) HandleListTemplateSnippets(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	snippets, err := h.pg.Q.GetTemplateSnippets(c.Context(), guildID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get template snippets")
+		return err
+	}
+
+	res := make([]wire.TemplateSnippetWire, len(snippets))
+	for i, snippet := range snippets {
+		res[i] = templateSnippetModelToWire(snippet)
+	}
+
+	return c.JSON(wire.TemplateSnippetListResponseWire{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *TemplatesHandler) HandleUpdateTemplateSnippet(c *fiber.Ctx, req wire.TemplateSnippetUpdateRequestWire) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	name := c.Params("name")
+	if !wire.IsValidSnippetName(name) {
+		return helpers.BadRequest("invalid_name", "The snippet name can only contain letters, numbers, dashes and underscores.")
+	}
+
+	if diagnostic := h.lintContext(c.Context()).Lint("content", req.Content); diagnostic != nil {
+		return helpers.BadRequest("invalid_template", fmt.Sprintf("The snippet is not a valid template: %s", diagnostic.Message))
+	}
+
+	_, err := h.pg.Q.GetTemplateSnippet(c.Context(), pgmodel.GetTemplateSnippetParams{
+		GuildID: guildID,
+		Name:    name,
+	})
+	if err == sql.ErrNoRows {
+		count, err := h.pg.Q.CountTemplateSnippets(c.Context(), guildID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to count template snippets")
+			return err
+		}
+
+		if count >= maxGuildSnippets {
+			return helpers.BadRequest("too_many_snippets", fmt.Sprintf("A server can't have more than %d snippets.", maxGuildSnippets))
+		}
+	} else if err != nil {
+		log.Error().Err(err).Msg("Failed to get template snippet")
+		return err
+	}
+
+	snippet, err := h.pg.Q.UpsertTemplateSnippet(c.Context(), pgmodel.UpsertTemplateSnippetParams{
+		GuildID:   guildID,
+		Name:      name,
+		Content:   req.Content,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update template snippet")
+		return err
+	}
+
+	return c.JSON(wire.TemplateSnippetUpdateResponseWire{
+		Success: true,
+		Data:    templateSnippetModelToWire(snippet),
+	})
+}
+
+func (h *TemplatesHandler) HandleDeleteTemplateSnippet(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	deleted, err := h.pg.Q.DeleteTemplateSnippet(c.Context(), pgmodel.DeleteTemplateSnippetParams{
+		GuildID: guildID,
+		Name:    c.Params("name"),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete template snippet")
+		return err
+	}
+
+	if deleted == 0 {
+		return helpers.NotFound("unknown_snippet", "The snippet does not exist.")
+	}
+
+	return c.JSON(wire.TemplateSnippetDeleteResponseWire{
+		Success: true,
+		Data:    struct{}{},
+	})
+}
+
+func templateSnippetModelToWire(model pgmodel.TemplateSnippet) wire.TemplateSnippetWire {
+	return wire.TemplateSnippetWire{
+		Name:      model.Name,
+		Content:   model.Content,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
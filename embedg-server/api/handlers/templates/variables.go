@@ -0,0 +1,52 @@
+package templates
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/wire"
+	"github.com/merlinfuchs/embed-generator/embedg-server/variables_migration"
+	"github.com/rs/zerolog/log"
+)
+
+// HandleConvertVariables converts the legacy variables of the guild to templates.
+// Without apply it only returns the changes that would be made.
+func (h *TemplatesHandler) HandleConvertVariables(c *fiber.Ctx, req wire.TemplateVariablesConvertRequestWire) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	changes, err := variables_migration.New(h.pg).ConvertGuild(c.Context(), guildID, req.Apply)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to convert variables")
+		return err
+	}
+
+	converted := 0
+	res := make([]wire.TemplateVariablesChangeWire, len(changes))
+	for i, change := range changes {
+		converted += change.Converted
+
+		unsupported := change.Unsupported
+		if unsupported == nil {
+			unsupported = []string{}
+		}
+
+		res[i] = wire.TemplateVariablesChangeWire{
+			Type:        string(change.Type),
+			ID:          change.ID,
+			Name:        change.Name,
+			Converted:   change.Converted,
+			Unsupported: unsupported,
+			Data:        change.Data,
+		}
+	}
+
+	return c.JSON(wire.TemplateVariablesConvertResponseWire{
+		Success: true,
+		Data: wire.TemplateVariablesConvertResponseDataWire{
+			Applied:   req.Apply,
+			Converted: converted,
+			Changes:   res,
+		},
+	})
+}
@@ -0,0 +1,153 @@
+package translations
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/access"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/helpers"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/wire"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/merlinfuchs/embed-generator/embedg-server/model"
+	"github.com/rs/zerolog/log"
+)
+
+const maxGuildTranslations = 5000
+
+type TranslationsHandler struct {
+	pg *postgres.PostgresStore
+	am *access.AccessManager
+}
+
+func New(pg *postgres.PostgresStore, am *access.AccessManager) *TranslationsHandler {
+	return &TranslationsHandler{
+		pg: pg,
+		am: am,
+	}
+}
+
+func (h *TranslationsHandler) HandleListTranslations(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	rows, err := h.pg.Q.GetGuildTranslations(c.Context(), guildID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get translations")
+		return err
+	}
+
+	res := make([]wire.TranslationWire, len(rows))
+	for i, row := range rows {
+		res[i] = wire.TranslationWire{
+			Locale:    row.Locale,
+			Key:       row.Key,
+			Value:     row.Value,
+			UpdatedAt: row.UpdatedAt,
+		}
+	}
+
+	return c.JSON(wire.TranslationListResponseWire{
+		Success: true,
+		Data:    res,
+	})
+}
+
+// HandleUpdateTranslations replaces all translations of the locale with the translations from the request.
+func (h *TranslationsHandler) HandleUpdateTranslations(c *fiber.Ctx, req wire.TranslationsUpdateRequestWire) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	locale, err := localeParam(c)
+	if err != nil {
+		return err
+	}
+
+	total, err := h.pg.Q.CountGuildTranslations(c.Context(), guildID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to count translations")
+		return err
+	}
+
+	existing, err := h.pg.GetTranslationsForLocales(c.Context(), guildID, []string{locale})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get translations")
+		return err
+	}
+
+	if int(total)-len(existing)+len(req.Translations) > maxGuildTranslations {
+		return helpers.BadRequest("too_many_translations", fmt.Sprintf("A server can't have more than %d translations.", maxGuildTranslations))
+	}
+
+	translations, err := h.pg.ReplaceTranslationsForLocale(c.Context(), guildID, locale, req.Translations)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to replace translations")
+		return err
+	}
+
+	sort.Slice(translations, func(i, j int) bool {
+		return translations[i].Key < translations[j].Key
+	})
+
+	res := make([]wire.TranslationWire, len(translations))
+	for i, translation := range translations {
+		res[i] = translationModelToWire(translation)
+	}
+
+	return c.JSON(wire.TranslationsUpdateResponseWire{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *TranslationsHandler) HandleDeleteTranslations(c *fiber.Ctx) error {
+	guildID := c.Query("guild_id")
+	if err := h.am.CheckGuildAccessForRequest(c, guildID); err != nil {
+		return err
+	}
+
+	locale, err := localeParam(c)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := h.pg.Q.DeleteGuildTranslationsForLocale(c.Context(), pgmodel.DeleteGuildTranslationsForLocaleParams{
+		GuildID: guildID,
+		Locale:  locale,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to delete translations")
+		return err
+	}
+
+	if deleted == 0 {
+		return helpers.NotFound("unknown_locale", "There are no translations for this locale.")
+	}
+
+	return c.JSON(wire.TranslationsDeleteResponseWire{
+		Success: true,
+		Data:    struct{}{},
+	})
+}
+
+func localeParam(c *fiber.Ctx) (string, error) {
+	locale := c.Params("locale")
+	if !wire.IsValidLocale(locale) {
+		return "", helpers.BadRequest("invalid_locale", "The locale is invalid.")
+	}
+	return locale, nil
+}
+
+func translationModelToWire(translation model.Translation) wire.TranslationWire {
+	return wire.TranslationWire{
+		Locale:    translation.Locale,
+		Key:       translation.Key,
+		Value:     translation.Value,
+		UpdatedAt: translation.UpdatedAt,
+	}
+}
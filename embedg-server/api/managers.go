@@ -8,6 +8,8 @@ import (
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/session"
 	"github.com/merlinfuchs/embed-generator/embedg-server/bot"
 	"github.com/merlinfuchs/embed-generator/embedg-server/custom_bots"
+	"github.com/merlinfuchs/embed-generator/embedg-server/event_triggers"
+	"github.com/merlinfuchs/embed-generator/embedg-server/kv_entries"
 	"github.com/merlinfuchs/embed-generator/embedg-server/scheduled_messages"
 )
 
@@ -17,6 +19,8 @@ type managers struct {
 	premium           *premium.PremiumManager
 	customBots        *custom_bots.CustomBotManager
 	scheduledMessages *scheduled_messages.ScheduledMessageManager
+	eventTriggers     *event_triggers.EventTriggerManager
+	kvEntries         *kv_entries.KVEntryManager
 
 	actionParser  *parser.ActionParser
 	actionHandler *handler.ActionHandler
@@ -28,10 +32,12 @@ func createManagers(stores *Stores, bot *bot.Bot) *managers {
 	premiumManager := premium.New(stores.PG, bot)
 
 	actionParser := parser.New(accessManager, stores.PG, bot.State)
-	actionHandler := handler.New(stores.PG, actionParser, premiumManager)
+	actionHandler := handler.New(stores.PG, actionParser, premiumManager, bot.State, bot.Rest)
 
-	customBots := custom_bots.NewCustomBotManager(stores.PG, actionHandler)
+	eventTriggers := event_triggers.NewEventTriggerManager(stores.PG, actionParser, bot, premiumManager)
+	customBots := custom_bots.NewCustomBotManager(stores.PG, actionHandler, eventTriggers)
 	scheduledMessages := scheduled_messages.NewScheduledMessageManager(stores.PG, actionParser, bot, premiumManager)
+	kvEntries := kv_entries.NewKVEntryManager(stores.PG)
 
 	bot.ActionHandler = actionHandler
 	bot.ActionParser = actionParser
@@ -42,6 +48,8 @@ func createManagers(stores *Stores, bot *bot.Bot) *managers {
 		premium:           premiumManager,
 		customBots:        customBots,
 		scheduledMessages: scheduledMessages,
+		eventTriggers:     eventTriggers,
+		kvEntries:         kvEntries,
 		actionParser:      actionParser,
 		actionHandler:     actionHandler,
 	}
@@ -11,15 +11,19 @@ import (
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/auth"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/custom_bots"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/embed_links"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/event_triggers"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/guilds"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/health"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/images"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/interaction"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/kv_entries"
 	premium_handler "github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/premium"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/saved_messages"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/scheduled_messages"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/send_message"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/shared_messages"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/templates"
+	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/translations"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/handlers/users"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/helpers"
 	"github.com/merlinfuchs/embed-generator/embedg-server/api/session"
@@ -34,6 +38,7 @@ func registerRoutes(app *fiber.App, stores *Stores, bot *bot.Bot, managers *mana
 	healthGroup := app.Group("/api/health")
 	healthGroup.Get("/", healthHandler.HandleHealth)
 	app.Get("/api/health/shard-list", healthHandler.HandleHealthShardList)
+	app.Get("/api/health/template-cache", healthHandler.HandleHealthTemplateCache)
 
 	authHandler := auth.New(stores.PG, bot, managers.session)
 	app.Get("/api/auth/login", authHandler.HandleAuthRedirect)
@@ -54,6 +59,9 @@ func registerRoutes(app *fiber.App, stores *Stores, bot *bot.Bot, managers *mana
 	savedMessagesGroup.Patch("/", helpers.WithRequestBodyValidated(savedMessagesHandler.HandleImportSavedMessages))
 	savedMessagesGroup.Put("/:messageID", helpers.WithRequestBodyValidated(savedMessagesHandler.HandleUpdateSavedMessage))
 	savedMessagesGroup.Delete("/:messageID", savedMessagesHandler.HandleDeleteSavedMessage)
+	savedMessagesGroup.Get("/:messageID/variants", savedMessagesHandler.HandleListSavedMessageVariants)
+	savedMessagesGroup.Put("/:messageID/variants/:locale", helpers.WithRequestBodyValidated(savedMessagesHandler.HandleUpdateSavedMessageVariant))
+	savedMessagesGroup.Delete("/:messageID/variants/:locale", savedMessagesHandler.HandleDeleteSavedMessageVariant)
 
 	sharedMessageHandler := shared_messages.New(bot, stores.PG)
 	sharedMessagesGroup := app.Group("/api/shared-messages")
@@ -89,12 +97,18 @@ func registerRoutes(app *fiber.App, stores *Stores, bot *bot.Bot, managers *mana
 	app.Put("/api/custom-bot/presence", sessionMiddleware.SessionRequired(), helpers.WithRequestBodyValidated(customBotHandler.HandleUpdateCustomBotPresence))
 	app.Get("/api/custom-bot", sessionMiddleware.SessionRequired(), customBotHandler.HandleGetCustomBot)
 	app.Delete("/api/custom-bot", sessionMiddleware.SessionRequired(), customBotHandler.HandleDisableCustomBot)
+	app.Get("/api/custom-bot/status", sessionMiddleware.SessionRequired(), customBotHandler.HandleGetCustomBotStatus)
 	app.Get("/api/custom-bot/commands", sessionMiddleware.SessionRequired(), customBotHandler.HandleListCustomCommands)
+	app.Get("/api/custom-bot/commands/export", sessionMiddleware.SessionRequired(), customBotHandler.HandleExportCustomCommands)
+	app.Get("/api/custom-bot/commands/deploy/plan", sessionMiddleware.SessionRequired(), customBotHandler.HandleGetCustomCommandsDeployPlan)
+	app.Get("/api/custom-bot/commands/deployments", sessionMiddleware.SessionRequired(), customBotHandler.HandleListCustomCommandDeployments)
 	app.Get("/api/custom-bot/commands/:commandID", sessionMiddleware.SessionRequired(), customBotHandler.HandleGetCustomCommand)
 	app.Post("/api/custom-bot/commands", sessionMiddleware.SessionRequired(), helpers.WithRequestBodyValidated(customBotHandler.HandleCreateCustomCommand))
 	app.Put("/api/custom-bot/commands/:commandID", sessionMiddleware.SessionRequired(), helpers.WithRequestBodyValidated(customBotHandler.HandleUpdateCustomCommand))
 	app.Delete("/api/custom-bot/commands/:commandID", sessionMiddleware.SessionRequired(), customBotHandler.HandleDeleteCustomCommand)
 	app.Post("/api/custom-bot/commands/deploy", sessionMiddleware.SessionRequired(), customBotHandler.HandleDeployCustomCommands)
+	app.Post("/api/custom-bot/commands/deployments/:deploymentID/rollback", sessionMiddleware.SessionRequired(), customBotHandler.HandleRollbackCustomCommandDeployment)
+	app.Post("/api/custom-bot/commands/import", sessionMiddleware.SessionRequired(), helpers.WithRequestBodyValidated(customBotHandler.HandleImportCustomCommands))
 	app.Post("/api/gateway/:customBotID", customBotHandler.HandleCustomBotInteraction)
 
 	interactionHandler := interaction.New(bot)
@@ -113,6 +127,40 @@ func registerRoutes(app *fiber.App, stores *Stores, bot *bot.Bot, managers *mana
 	scheduledMessagesGroup.Put("/:messageID", helpers.WithRequestBodyValidated(scheduledMessagesHandler.HandleUpdateScheduledMessage))
 	scheduledMessagesGroup.Delete("/:messageID", scheduledMessagesHandler.HandleDeleteScheduledMessage)
 
+	eventTriggersHandler := event_triggers.New(stores.PG, managers.access, managers.premium, managers.actionParser)
+	eventTriggersGroup := app.Group("/api/event-triggers", sessionMiddleware.SessionRequired())
+	eventTriggersGroup.Get("/", eventTriggersHandler.HandleListEventTriggers)
+	eventTriggersGroup.Post("/", helpers.WithRequestBodyValidated(eventTriggersHandler.HandleCreateEventTrigger))
+	eventTriggersGroup.Get("/:triggerID", eventTriggersHandler.HandleGetEventTrigger)
+	eventTriggersGroup.Put("/:triggerID", helpers.WithRequestBodyValidated(eventTriggersHandler.HandleUpdateEventTrigger))
+	eventTriggersGroup.Delete("/:triggerID", eventTriggersHandler.HandleDeleteEventTrigger)
+
+	kvEntriesHandler := kv_entries.New(stores.PG, managers.access, managers.premium)
+	kvEntriesGroup := app.Group("/api/kv-entries", sessionMiddleware.SessionRequired())
+	kvEntriesGroup.Get("/", kvEntriesHandler.HandleListKVEntries)
+	kvEntriesGroup.Get("/export", kvEntriesHandler.HandleExportKVEntries)
+	kvEntriesGroup.Post("/import", helpers.WithRequestBodyValidated(kvEntriesHandler.HandleImportKVEntries))
+	kvEntriesGroup.Get("/:key", kvEntriesHandler.HandleGetKVEntry)
+	kvEntriesGroup.Put("/:key", helpers.WithRequestBodyValidated(kvEntriesHandler.HandleUpdateKVEntry))
+	kvEntriesGroup.Delete("/:key", kvEntriesHandler.HandleDeleteKVEntry)
+
+	translationsHandler := translations.New(stores.PG, managers.access)
+	translationsGroup := app.Group("/api/translations", sessionMiddleware.SessionRequired())
+	translationsGroup.Get("/", translationsHandler.HandleListTranslations)
+	translationsGroup.Put("/:locale", helpers.WithRequestBodyValidated(translationsHandler.HandleUpdateTranslations))
+	translationsGroup.Delete("/:locale", translationsHandler.HandleDeleteTranslations)
+
+	templatesHandler := templates.New(stores.PG, bot, managers.access, managers.premium)
+	app.Post("/api/templates/lint", sessionMiddleware.SessionRequired(), helpers.WithRequestBodyValidated(templatesHandler.HandleLintTemplates))
+	app.Post("/api/templates/preview", sessionMiddleware.SessionRequired(), helpers.WithRequestBodyValidated(templatesHandler.HandleRenderTemplatePreview))
+	app.Get("/api/templates/snippets", sessionMiddleware.SessionRequired(), templatesHandler.HandleListTemplateSnippets)
+	app.Put("/api/templates/snippets/:name", sessionMiddleware.SessionRequired(), helpers.WithRequestBodyValidated(templatesHandler.HandleUpdateTemplateSnippet))
+	app.Delete("/api/templates/snippets/:name", sessionMiddleware.SessionRequired(), templatesHandler.HandleDeleteTemplateSnippet)
+	app.Get("/api/templates/http-hosts", sessionMiddleware.SessionRequired(), templatesHandler.HandleListTemplateHTTPHosts)
+	app.Post("/api/templates/http-hosts", sessionMiddleware.SessionRequired(), helpers.WithRequestBodyValidated(templatesHandler.HandleCreateTemplateHTTPHost))
+	app.Delete("/api/templates/http-hosts/:host", sessionMiddleware.SessionRequired(), templatesHandler.HandleDeleteTemplateHTTPHost)
+	app.Post("/api/templates/convert-variables", sessionMiddleware.SessionRequired(), helpers.WithRequestBodyValidated(templatesHandler.HandleConvertVariables))
+
 	embedLinksHandler := embed_links.New(stores.PG)
 	app.Post("/api/embed-links", helpers.WithRequestBodyValidated(embedLinksHandler.HandleCreateEmbedLink))
 	app.Get("/api/embed-links/:linkID/oembed", embedLinksHandler.HandleRenderEmbedLinkJSON)
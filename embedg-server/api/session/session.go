@@ -45,7 +45,7 @@ func (s *SessionManager) GetSession(c *fiber.Ctx) (*Session, error) {
 		return nil, err
 	}
 
-	model, err := s.pg.Q.GetSession(c.Context(), tokenHash)
+	model, err := s.pg.GetSession(c.Context(), tokenHash)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -70,7 +70,7 @@ func (s *SessionManager) CreateSession(ctx context.Context, userID string, guild
 		return "", err
 	}
 
-	_, err = s.pg.Q.InsertSession(ctx, pgmodel.InsertSessionParams{
+	_, err = s.pg.InsertSession(ctx, pgmodel.InsertSessionParams{
 		TokenHash:   tokenHash,
 		UserID:      userID,
 		GuildIds:    guildIDs,
@@ -2,6 +2,8 @@ package wire
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
 	"regexp"
 	"strings"
 	"time"
@@ -79,104 +81,525 @@ type CustomBotDisableResponseDataWire struct{}
 
 type CustomBotDisableResponseWire APIResponse[CustomBotDisableResponseDataWire]
 
+type CustomBotStatusWire struct {
+	Gateway      CustomBotGatewayStatusWire      `json:"gateway"`
+	Interactions CustomBotInteractionsStatusWire `json:"interactions"`
+	Commands     CustomBotCommandsStatusWire     `json:"commands"`
+}
+
+type CustomBotGatewayStatusWire struct {
+	Connected        bool        `json:"connected"`
+	TokenValid       bool        `json:"token_valid"`
+	ReadyAt          null.Time   `json:"ready_at"`
+	DisconnectedAt   null.Time   `json:"disconnected_at"`
+	DisconnectReason null.String `json:"disconnect_reason"`
+	LatencyMs        null.Int    `json:"latency_ms"`
+	Reconnects       int         `json:"reconnects"`
+	// Whether the session receives member events, null if the custom bot hasn't connected yet.
+	MembersIntent null.Bool `json:"members_intent"`
+}
+
+type CustomBotInteractionsStatusWire struct {
+	EndpointURL            string    `json:"endpoint_url"`
+	LastInteractionAt      null.Time `json:"last_interaction_at"`
+	LastSignatureFailureAt null.Time `json:"last_signature_failure_at"`
+}
+
+type CustomBotCommandsStatusWire struct {
+	InSync   bool        `json:"in_sync"`
+	Missing  []string    `json:"missing"`
+	Outdated []string    `json:"outdated"`
+	Unknown  []string    `json:"unknown"`
+	Error    null.String `json:"error"`
+}
+
+type CustomBotStatusResponseWire APIResponse[CustomBotStatusWire]
+
+// Custom commands have the same types as Discord's application commands.
+const (
+	CustomCommandTypeChatInput = 1
+	CustomCommandTypeUser      = 2
+	CustomCommandTypeMessage   = 3
+)
+
 type CustomCommandWire struct {
-	ID          string                       `json:"id"`
-	Name        string                       `json:"name"`
-	Description string                       `json:"description"`
-	Enabled     bool                         `json:"enabled"`
-	Parameters  []CustomCommandParameterWire `json:"parameters"`
-	Actions     json.RawMessage              `json:"actions"`
-	CreatedAt   time.Time                    `json:"created_at"`
-	UpdatedAt   time.Time                    `json:"updated_at"`
-	DeployedAt  null.Time                    `json:"deployed_at"`
+	ID                       string                       `json:"id"`
+	Type                     int                          `json:"type"`
+	Name                     string                       `json:"name"`
+	Description              string                       `json:"description"`
+	NameLocalizations        map[string]string            `json:"name_localizations"`
+	DescriptionLocalizations map[string]string            `json:"description_localizations"`
+	DefaultMemberPermissions null.String                  `json:"default_member_permissions"`
+	Contexts                 []int                        `json:"contexts"`
+	Enabled                  bool                         `json:"enabled"`
+	Parameters               []CustomCommandParameterWire `json:"parameters"`
+	Actions                  json.RawMessage              `json:"actions"`
+	CreatedAt                time.Time                    `json:"created_at"`
+	UpdatedAt                time.Time                    `json:"updated_at"`
+	DeployedAt               null.Time                    `json:"deployed_at"`
 }
 
 type CustomCommandParameterWire struct {
-	ID          int    `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Type        int    `json:"type"`
+	ID                       int                                `json:"id"`
+	Name                     string                             `json:"name"`
+	Description              string                             `json:"description"`
+	NameLocalizations        map[string]string                  `json:"name_localizations,omitempty"`
+	DescriptionLocalizations map[string]string                  `json:"description_localizations,omitempty"`
+	Type                     int                                `json:"type"`
+	Required                 null.Bool                          `json:"required,omitempty"`
+	Choices                  []CustomCommandParameterChoiceWire `json:"choices,omitempty"`
+	MinValue                 null.Float                         `json:"min_value,omitempty"`
+	MaxValue                 null.Float                         `json:"max_value,omitempty"`
+	MinLength                null.Int                           `json:"min_length,omitempty"`
+	MaxLength                null.Int                           `json:"max_length,omitempty"`
+	ChannelTypes             []int                              `json:"channel_types,omitempty"`
+	Autocomplete             *CustomCommandAutocompleteWire     `json:"autocomplete,omitempty"`
 }
 
-type CustomCommandsListResponseWire APIResponse[[]CustomCommandWire]
+// IsRequired returns whether the parameter is required, parameters are required unless they have been marked as optional.
+func (p CustomCommandParameterWire) IsRequired() bool {
+	return !p.Required.Valid || p.Required.Bool
+}
 
-type CustomCommandGetResponseWire APIResponse[CustomCommandWire]
+type CustomCommandParameterChoiceWire struct {
+	Name              string            `json:"name"`
+	NameLocalizations map[string]string `json:"name_localizations,omitempty"`
+	// Value is a string for string parameters and a number for integer and number parameters.
+	Value interface{} `json:"value"`
+}
 
-type CustomCommandCreateRequestWire struct {
-	Name        string                       `json:"name"`
-	Description string                       `json:"description"`
-	Parameters  []CustomCommandParameterWire `json:"parameters"`
-	Actions     json.RawMessage              `json:"actions"`
+// Sources of autocomplete suggestions for custom command parameters.
+const (
+	CustomCommandAutocompleteStatic   = "static"
+	CustomCommandAutocompleteKV       = "kv"
+	CustomCommandAutocompleteTemplate = "template"
+)
+
+// CustomCommandAutocompleteWire configures where the suggestions for a parameter come from.
+// The template outputs one suggestion per line or a JSON array of suggestions.
+type CustomCommandAutocompleteWire struct {
+	Type      string   `json:"type"`
+	Values    []string `json:"values,omitempty"`
+	KeyPrefix string   `json:"key_prefix,omitempty"`
+	Template  string   `json:"template,omitempty"`
 }
 
-var commandNameRegex = regexp.MustCompile(`^([-_\p{L}\p{N}]+ ?){3}$`)
+const maxAutocompleteValues = 100
+const maxAutocompleteKeyPrefixLength = 256
+const maxAutocompleteTemplateLength = 4000
 
-func (r CustomCommandCreateRequestWire) Validate() error {
-	err := validation.ValidateStruct(&r,
-		validation.Field(&r.Name, validation.Required, validation.Length(1, 32), validation.Match(commandNameRegex)),
-		validation.Field(&r.Description, validation.Required, validation.Length(1, 100)),
+func (a CustomCommandAutocompleteWire) Validate() error {
+	return validation.ValidateStruct(&a,
+		validation.Field(&a.Type, validation.Required, validation.In(
+			CustomCommandAutocompleteStatic,
+			CustomCommandAutocompleteKV,
+			CustomCommandAutocompleteTemplate,
+		)),
+		validation.Field(&a.Values,
+			validation.When(a.Type == CustomCommandAutocompleteStatic, validation.Required),
+			validation.Length(0, maxAutocompleteValues),
+			validation.Each(validation.Required, validation.Length(1, 100)),
+		),
+		validation.Field(&a.KeyPrefix,
+			validation.When(a.Type == CustomCommandAutocompleteKV, validation.Required),
+			validation.Length(0, maxAutocompleteKeyPrefixLength),
+		),
+		validation.Field(&a.Template,
+			validation.When(a.Type == CustomCommandAutocompleteTemplate, validation.Required),
+			validation.Length(0, maxAutocompleteTemplateLength),
+		),
 	)
-	if err != nil {
-		return err
-	}
+}
 
-	for _, p := range r.Parameters {
+const (
+	customCommandParameterTypeString  = 3
+	customCommandParameterTypeInteger = 4
+	customCommandParameterTypeChannel = 7
+	customCommandParameterTypeNumber  = 10
+)
+
+const maxCustomCommandParameterChoices = 25
+
+// validChannelTypes are the channel types that a channel parameter can be limited to.
+var validChannelTypes = []interface{}{0, 1, 2, 3, 4, 5, 10, 11, 12, 13, 14, 15, 16}
+
+// validCommandContexts are the interaction contexts that a command can be used in.
+var validCommandContexts = []interface{}{0, 1, 2}
+
+func validateCustomCommandParameters(params []CustomCommandParameterWire) error {
+	optional := false
+	for _, p := range params {
 		err := validation.ValidateStruct(&p,
 			validation.Field(&p.Name, validation.Required, validation.Length(1, 32)),
 			validation.Field(&p.Description, validation.Required, validation.Length(1, 100)),
+			validation.Field(&p.NameLocalizations, validation.By(localizationsRule(32))),
+			validation.Field(&p.DescriptionLocalizations, validation.By(localizationsRule(100))),
 			validation.Field(&p.Type, validation.Required, validation.In(3, 4, 5, 6, 7, 8, 10, 11)),
+			validation.Field(&p.Choices,
+				validation.When(
+					p.Type != customCommandParameterTypeString && p.Type != customCommandParameterTypeInteger && p.Type != customCommandParameterTypeNumber,
+					validation.Empty.Error("choices are only supported for string, integer and number parameters"),
+				),
+				validation.Length(0, maxCustomCommandParameterChoices),
+				validation.By(choicesRule(p.Type)),
+			),
+			validation.Field(&p.MinValue, validation.When(
+				p.Type != customCommandParameterTypeInteger && p.Type != customCommandParameterTypeNumber,
+				validation.Empty.Error("min value is only supported for integer and number parameters"),
+			)),
+			validation.Field(&p.MaxValue, validation.When(
+				p.Type != customCommandParameterTypeInteger && p.Type != customCommandParameterTypeNumber,
+				validation.Empty.Error("max value is only supported for integer and number parameters"),
+			)),
+			validation.Field(&p.MinLength,
+				validation.When(p.Type != customCommandParameterTypeString, validation.Empty.Error("min length is only supported for string parameters")),
+				validation.Min(0), validation.Max(6000),
+			),
+			validation.Field(&p.MaxLength,
+				validation.When(p.Type != customCommandParameterTypeString, validation.Empty.Error("max length is only supported for string parameters")),
+				validation.Min(1), validation.Max(6000),
+			),
+			validation.Field(&p.ChannelTypes,
+				validation.When(p.Type != customCommandParameterTypeChannel, validation.Empty.Error("channel types are only supported for channel parameters")),
+				validation.Each(validation.In(validChannelTypes...)),
+			),
+			validation.Field(&p.Autocomplete,
+				validation.When(
+					p.Type != customCommandParameterTypeString && p.Type != customCommandParameterTypeInteger,
+					validation.Nil.Error("autocomplete is only supported for string and integer parameters"),
+				),
+				validation.When(len(p.Choices) != 0, validation.Nil.Error("autocomplete can't be combined with choices")),
+			),
 		)
 		if err != nil {
 			return err
 		}
+
+		// Discord's API drops a max value of 0, so it would silently allow any value
+		if p.MaxValue.Valid && p.MaxValue.Float64 == 0 {
+			return fmt.Errorf("max value of parameter %s can't be 0", p.Name)
+		}
+		if p.MinValue.Valid && p.MaxValue.Valid && p.MaxValue.Float64 < p.MinValue.Float64 {
+			return fmt.Errorf("max value of parameter %s must not be smaller than its min value", p.Name)
+		}
+		if p.MinLength.Valid && p.MaxLength.Valid && p.MaxLength.Int64 < p.MinLength.Int64 {
+			return fmt.Errorf("max length of parameter %s must not be smaller than its min length", p.Name)
+		}
+
+		// Discord requires all required options to come before the optional ones
+		if !p.IsRequired() {
+			optional = true
+		} else if optional {
+			return fmt.Errorf("required parameter %s must come before the optional parameters", p.Name)
+		}
 	}
 
 	return nil
 }
 
+func choicesRule(paramType int) validation.RuleFunc {
+	return func(value interface{}) error {
+		choices, _ := value.([]CustomCommandParameterChoiceWire)
+		for _, choice := range choices {
+			if choice.Name == "" || len(choice.Name) > 100 {
+				return fmt.Errorf("choice names must be between 1 and 100 characters long")
+			}
+			if err := localizationsRule(100)(choice.NameLocalizations); err != nil {
+				return err
+			}
+
+			switch v := choice.Value.(type) {
+			case string:
+				if paramType != customCommandParameterTypeString {
+					return fmt.Errorf("value of choice %s must be a number", choice.Name)
+				}
+				if v == "" || len(v) > 100 {
+					return fmt.Errorf("value of choice %s must be between 1 and 100 characters long", choice.Name)
+				}
+			case float64:
+				if paramType == customCommandParameterTypeString {
+					return fmt.Errorf("value of choice %s must be a string", choice.Name)
+				}
+				if paramType == customCommandParameterTypeInteger && v != math.Trunc(v) {
+					return fmt.Errorf("value of choice %s must be an integer", choice.Name)
+				}
+			default:
+				return fmt.Errorf("value of choice %s must be a string or number", choice.Name)
+			}
+		}
+		return nil
+	}
+}
+
+func localizationsRule(maxLength int) validation.RuleFunc {
+	return func(value interface{}) error {
+		localizations, _ := value.(map[string]string)
+		for locale, text := range localizations {
+			if !IsValidLocale(locale) {
+				return fmt.Errorf("%s is not a valid locale", locale)
+			}
+			if text == "" || len(text) > maxLength {
+				return fmt.Errorf("localization for %s must be between 1 and %d characters long", locale, maxLength)
+			}
+		}
+		return nil
+	}
+}
+
+type CustomCommandsListResponseWire APIResponse[[]CustomCommandWire]
+
+type CustomCommandGetResponseWire APIResponse[CustomCommandWire]
+
+type CustomCommandCreateRequestWire struct {
+	Type                     int                          `json:"type,omitempty"`
+	Name                     string                       `json:"name"`
+	Description              string                       `json:"description"`
+	NameLocalizations        map[string]string            `json:"name_localizations,omitempty"`
+	DescriptionLocalizations map[string]string            `json:"description_localizations,omitempty"`
+	DefaultMemberPermissions null.String                  `json:"default_member_permissions,omitempty"`
+	Contexts                 []int                        `json:"contexts,omitempty"`
+	Parameters               []CustomCommandParameterWire `json:"parameters"`
+	Actions                  json.RawMessage              `json:"actions"`
+}
+
+var commandNameRegex = regexp.MustCompile(`^([-_\p{L}\p{N}]+ ?){3}$`)
+
+// permissionsRegex matches a permission bit set in the string format that Discord uses.
+var permissionsRegex = regexp.MustCompile(`^[0-9]{1,20}$`)
+
+func (r CustomCommandCreateRequestWire) Validate() error {
+	isChatInput := r.Type == 0 || r.Type == CustomCommandTypeChatInput
+
+	err := validation.ValidateStruct(&r,
+		validation.Field(&r.Type, validation.In(0, CustomCommandTypeChatInput, CustomCommandTypeUser, CustomCommandTypeMessage)),
+		validation.Field(&r.Name, validation.Required, validation.Length(1, 32), validation.When(isChatInput, validation.Match(commandNameRegex))),
+		validation.Field(&r.Description, validation.When(
+			isChatInput,
+			validation.Required, validation.Length(1, 100),
+		).Else(
+			validation.Empty.Error("context menu commands can't have a description"),
+		)),
+		validation.Field(&r.NameLocalizations, validation.By(localizationsRule(32))),
+		validation.Field(&r.DescriptionLocalizations, validation.When(
+			isChatInput,
+			validation.By(localizationsRule(100)),
+		).Else(
+			validation.Empty.Error("context menu commands can't have a description"),
+		)),
+		validation.Field(&r.DefaultMemberPermissions, validation.Match(permissionsRegex)),
+		validation.Field(&r.Contexts, validation.Each(validation.In(validCommandContexts...))),
+		validation.Field(&r.Parameters, validation.When(
+			!isChatInput,
+			validation.Empty.Error("context menu commands can't have parameters"),
+		)),
+	)
+	if err != nil {
+		return err
+	}
+
+	return validateCustomCommandParameters(r.Parameters)
+}
+
 func (r *CustomCommandCreateRequestWire) Normalize() {
 	r.Name = strings.TrimSpace(r.Name)
+	if r.Type == 0 {
+		r.Type = CustomCommandTypeChatInput
+	}
 }
 
 type CustomCommandCreateResponseWire APIResponse[CustomCommandWire]
 
 type CustomCommandUpdateRequestWire struct {
-	Name        string                       `json:"name"`
-	Description string                       `json:"description"`
-	Enabled     bool                         `json:"enabled"`
-	Parameters  []CustomCommandParameterWire `json:"parameters"`
-	Actions     json.RawMessage              `json:"actions"`
+	Type                     int                          `json:"type,omitempty"`
+	Name                     string                       `json:"name"`
+	Description              string                       `json:"description"`
+	NameLocalizations        map[string]string            `json:"name_localizations,omitempty"`
+	DescriptionLocalizations map[string]string            `json:"description_localizations,omitempty"`
+	DefaultMemberPermissions null.String                  `json:"default_member_permissions,omitempty"`
+	Contexts                 []int                        `json:"contexts,omitempty"`
+	Enabled                  bool                         `json:"enabled"`
+	Parameters               []CustomCommandParameterWire `json:"parameters"`
+	Actions                  json.RawMessage              `json:"actions"`
 }
 
 func (r CustomCommandUpdateRequestWire) Validate() error {
+	isChatInput := r.Type == 0 || r.Type == CustomCommandTypeChatInput
+
 	err := validation.ValidateStruct(&r,
-		validation.Field(&r.Name, validation.Required, validation.Length(1, 32), validation.Match(commandNameRegex)),
-		validation.Field(&r.Description, validation.Required, validation.Length(1, 100)),
+		validation.Field(&r.Type, validation.In(0, CustomCommandTypeChatInput, CustomCommandTypeUser, CustomCommandTypeMessage)),
+		validation.Field(&r.Name, validation.Required, validation.Length(1, 32), validation.When(isChatInput, validation.Match(commandNameRegex))),
+		validation.Field(&r.Description, validation.When(
+			isChatInput,
+			validation.Required, validation.Length(1, 100),
+		).Else(
+			validation.Empty.Error("context menu commands can't have a description"),
+		)),
+		validation.Field(&r.NameLocalizations, validation.By(localizationsRule(32))),
+		validation.Field(&r.DescriptionLocalizations, validation.When(
+			isChatInput,
+			validation.By(localizationsRule(100)),
+		).Else(
+			validation.Empty.Error("context menu commands can't have a description"),
+		)),
+		validation.Field(&r.DefaultMemberPermissions, validation.Match(permissionsRegex)),
+		validation.Field(&r.Contexts, validation.Each(validation.In(validCommandContexts...))),
+		validation.Field(&r.Parameters, validation.When(
+			!isChatInput,
+			validation.Empty.Error("context menu commands can't have parameters"),
+		)),
 	)
 	if err != nil {
 		return err
 	}
 
-	for _, p := range r.Parameters {
-		err := validation.ValidateStruct(&p,
-			validation.Field(&p.Name, validation.Required, validation.Length(1, 32)),
-			validation.Field(&p.Description, validation.Required, validation.Length(1, 100)),
-			validation.Field(&p.Type, validation.Required, validation.In(3, 4, 5, 6, 7, 8, 10, 11)),
-		)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return validateCustomCommandParameters(r.Parameters)
 }
 
 func (r *CustomCommandUpdateRequestWire) Normalize() {
 	r.Name = strings.TrimSpace(r.Name)
+	if r.Type == 0 {
+		r.Type = CustomCommandTypeChatInput
+	}
 }
 
 type CustomCommandUpdateResponseWire APIResponse[CustomCommandWire]
 
 type CustomCommandDeleteResponseWire APIResponse[struct{}]
 
-type CustomCommandsDeployResponseWire APIResponse[struct{}]
+// Actions that a deployment takes for a single command.
+const (
+	CustomCommandChangeCreate = "create"
+	CustomCommandChangeUpdate = "update"
+	CustomCommandChangeDelete = "delete"
+)
+
+type CustomCommandChangeWire struct {
+	Action string `json:"action"`
+	Type   int    `json:"type"`
+	Name   string `json:"name"`
+}
+
+type CustomCommandsDeployPlanWire struct {
+	InSync  bool                      `json:"in_sync"`
+	Changes []CustomCommandChangeWire `json:"changes"`
+}
+
+type CustomCommandsDeployPlanResponseWire APIResponse[CustomCommandsDeployPlanWire]
+
+type CustomCommandsDeployResultWire struct {
+	Changes []CustomCommandChangeWire `json:"changes"`
+	// DeploymentID is null when there was nothing to deploy.
+	DeploymentID null.String `json:"deployment_id"`
+}
+
+type CustomCommandsDeployResponseWire APIResponse[CustomCommandsDeployResultWire]
+
+// CustomCommandDeployedWire is a command as it was registered with Discord by a deployment.
+type CustomCommandDeployedWire struct {
+	ID   string `json:"id"`
+	Type int    `json:"type"`
+	Name string `json:"name"`
+}
+
+type CustomCommandDeploymentWire struct {
+	ID            string                      `json:"id"`
+	ApplicationID string                      `json:"application_id"`
+	UserID        string                      `json:"user_id"`
+	Commands      []CustomCommandDeployedWire `json:"commands"`
+	Changes       []CustomCommandChangeWire   `json:"changes"`
+	RollbackOf    null.String                 `json:"rollback_of"`
+	// Partial is set when deploying failed midway and only some of the changes have been made.
+	Partial   bool      `json:"partial"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CustomCommandDeploymentsListResponseWire APIResponse[[]CustomCommandDeploymentWire]
+
+type CustomCommandDeploymentRollbackResponseWire APIResponse[CustomCommandsDeployResultWire]
+
+// CustomCommandBundleVersion is the version of the bundle format, it must be increased on breaking changes.
+const CustomCommandBundleVersion = 1
+
+// CustomCommandBundleWire contains all custom commands of a guild so they can be imported into another guild.
+type CustomCommandBundleWire struct {
+	Version    int                            `json:"version"`
+	ExportedAt time.Time                      `json:"exported_at"`
+	Commands   []CustomCommandBundleEntryWire `json:"commands"`
+}
+
+func (b CustomCommandBundleWire) Validate() error {
+	return validation.ValidateStruct(&b,
+		validation.Field(&b.Version, validation.Required, validation.In(CustomCommandBundleVersion).Error("unsupported bundle version")),
+		validation.Field(&b.Commands, validation.Required),
+	)
+}
+
+func (b *CustomCommandBundleWire) Normalize() {
+	for i := range b.Commands {
+		b.Commands[i].Normalize()
+	}
+}
+
+// CustomCommandBundleEntryWire is a single command in a bundle, it has the same fields as an update request.
+type CustomCommandBundleEntryWire CustomCommandUpdateRequestWire
+
+func (e CustomCommandBundleEntryWire) Validate() error {
+	return CustomCommandUpdateRequestWire(e).Validate()
+}
+
+func (e *CustomCommandBundleEntryWire) Normalize() {
+	(*CustomCommandUpdateRequestWire)(e).Normalize()
+}
+
+type CustomCommandsExportResponseWire APIResponse[CustomCommandBundleWire]
+
+// How commands in a bundle are handled that have the same name and type as an existing command.
+const (
+	CustomCommandImportConflictError   = "error"
+	CustomCommandImportConflictSkip    = "skip"
+	CustomCommandImportConflictReplace = "replace"
+)
+
+type CustomCommandsImportRequestWire struct {
+	Bundle     CustomCommandBundleWire `json:"bundle"`
+	OnConflict string                  `json:"on_conflict,omitempty"`
+}
+
+func (r CustomCommandsImportRequestWire) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Bundle),
+		validation.Field(&r.OnConflict, validation.In(
+			CustomCommandImportConflictError,
+			CustomCommandImportConflictSkip,
+			CustomCommandImportConflictReplace,
+		)),
+	)
+}
+
+func (r *CustomCommandsImportRequestWire) Normalize() {
+	r.Bundle.Normalize()
+	if r.OnConflict == "" {
+		r.OnConflict = CustomCommandImportConflictError
+	}
+}
+
+type CustomCommandsImportResponseDataWire struct {
+	Created           []CustomCommandWire                       `json:"created"`
+	Updated           []CustomCommandWire                       `json:"updated"`
+	Skipped           []string                                  `json:"skipped"`
+	MissingReferences []CustomCommandImportMissingReferenceWire `json:"missing_references"`
+}
+
+const (
+	CustomCommandImportReferenceRole         = "role"
+	CustomCommandImportReferenceSavedMessage = "saved_message"
+)
+
+// CustomCommandImportMissingReferenceWire is a role or saved message that the actions of an imported command refer to but that doesn't exist on the server.
+type CustomCommandImportMissingReferenceWire struct {
+	Command  string `json:"command"`
+	Type     string `json:"type"`
+	TargetID string `json:"target_id"`
+}
+
+type CustomCommandsImportResponseWire APIResponse[CustomCommandsImportResponseDataWire]
@@ -0,0 +1,77 @@
+package wire
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gopkg.in/guregu/null.v4"
+)
+
+type EventTriggerWire struct {
+	ID             string          `json:"id"`
+	CreatorID      string          `json:"creator_id"`
+	GuildID        string          `json:"guild_id"`
+	Event          string          `json:"event"`
+	Name           string          `json:"name"`
+	SavedMessageID null.String     `json:"saved_message_id"`
+	ChannelID      null.String     `json:"channel_id"`
+	Actions        json.RawMessage `json:"actions"`
+	Enabled        bool            `json:"enabled"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+type EventTriggerListResponseWire APIResponse[[]EventTriggerWire]
+
+type EventTriggerGetResponseWire APIResponse[EventTriggerWire]
+
+type EventTriggerCreateRequestWire struct {
+	Event          string          `json:"event"`
+	Name           string          `json:"name"`
+	SavedMessageID null.String     `json:"saved_message_id"`
+	ChannelID      null.String     `json:"channel_id"`
+	Actions        json.RawMessage `json:"actions"`
+	Enabled        bool            `json:"enabled"`
+}
+
+func (req EventTriggerCreateRequestWire) Validate() error {
+	return validateEventTrigger(req.Event, req.Name, req.SavedMessageID, req.ChannelID)
+}
+
+type EventTriggerCreateResponseWire APIResponse[EventTriggerWire]
+
+type EventTriggerUpdateRequestWire struct {
+	Event          string          `json:"event"`
+	Name           string          `json:"name"`
+	SavedMessageID null.String     `json:"saved_message_id"`
+	ChannelID      null.String     `json:"channel_id"`
+	Actions        json.RawMessage `json:"actions"`
+	Enabled        bool            `json:"enabled"`
+}
+
+func (req EventTriggerUpdateRequestWire) Validate() error {
+	return validateEventTrigger(req.Event, req.Name, req.SavedMessageID, req.ChannelID)
+}
+
+type EventTriggerUpdateResponseWire APIResponse[EventTriggerWire]
+
+type EventTriggerDeleteResponseWire APIResponse[struct{}]
+
+func validateEventTrigger(event string, name string, savedMessageID null.String, channelID null.String) error {
+	return validation.Errors{
+		"event": validation.Validate(event, validation.Required, validation.In("member_join", "member_leave", "member_boost")),
+		"name":  validation.Validate(name, validation.Required, validation.Length(1, 100)),
+		"channel_id": validation.Validate(channelID, validation.When(
+			// Members can't be sent a DM after they have left the server
+			event == "member_leave" && savedMessageID.Valid,
+			validation.By(func(interface{}) error {
+				if !channelID.Valid || channelID.String == "" {
+					return errors.New("a channel is required to send a message when a member leaves")
+				}
+				return nil
+			}),
+		)),
+	}.Filter()
+}
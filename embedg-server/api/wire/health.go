@@ -16,3 +16,11 @@ type ShardWire struct {
 	ShouldRetryOnRateLimit bool      `json:"should_retry_on_rate_limit"`
 	Suspicious             bool      `json:"suspicious"`
 }
+
+type TemplateCacheStatsWire struct {
+	Size      int     `json:"size"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	HitRate   float64 `json:"hit_rate"`
+}
@@ -0,0 +1,90 @@
+package wire
+
+import (
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gopkg.in/guregu/null.v4"
+)
+
+type KVEntryWire struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	ExpiresAt null.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type KVEntryListResponseDataWire struct {
+	Entries []KVEntryWire `json:"entries"`
+	Total   int           `json:"total"`
+}
+
+type KVEntryListResponseWire APIResponse[KVEntryListResponseDataWire]
+
+type KVEntryGetResponseWire APIResponse[KVEntryWire]
+
+type KVEntryUpdateRequestWire struct {
+	Value     string    `json:"value"`
+	ExpiresAt null.Time `json:"expires_at"`
+}
+
+func (req KVEntryUpdateRequestWire) Validate() error {
+	return validation.ValidateStruct(&req,
+		validation.Field(&req.Value, validation.By(kvValueSizeRule)),
+	)
+}
+
+type KVEntryUpdateResponseWire APIResponse[KVEntryWire]
+
+type KVEntryDeleteResponseWire APIResponse[struct{}]
+
+type KVEntryImportWire struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	ExpiresAt null.Time `json:"expires_at"`
+}
+
+func (req KVEntryImportWire) Validate() error {
+	return validation.ValidateStruct(&req,
+		validation.Field(&req.Key, validation.Required, validation.Length(1, 256)),
+		validation.Field(&req.Value, validation.By(kvValueSizeRule)),
+	)
+}
+
+type KVEntriesImportRequestWire struct {
+	Entries []KVEntryImportWire `json:"entries"`
+}
+
+func (req KVEntriesImportRequestWire) Validate() error {
+	return validation.ValidateStruct(&req,
+		validation.Field(&req.Entries, validation.Required, validation.Length(1, 1000)),
+	)
+}
+
+type KVEntriesImportResponseDataWire struct {
+	CreatedCount int `json:"created_count"`
+	UpdatedCount int `json:"updated_count"`
+}
+
+type KVEntriesImportResponseWire APIResponse[KVEntriesImportResponseDataWire]
+
+type KVEntriesExportResponseDataWire struct {
+	Entries []KVEntryImportWire `json:"entries"`
+	Total   int                 `json:"total"`
+}
+
+type KVEntriesExportResponseWire APIResponse[KVEntriesExportResponseDataWire]
+
+// maxKVValueSize is the maximum size of a value in bytes, the database enforces the same limit.
+const maxKVValueSize = 16 * 1024
+
+// kvValueSizeRule checks the size of the value in bytes, validation.Length counts runes instead.
+func kvValueSizeRule(value interface{}) error {
+	s, _ := value.(string)
+	if len(s) > maxKVValueSize {
+		return fmt.Errorf("the value must not be larger than %d bytes", maxKVValueSize)
+	}
+	return nil
+}
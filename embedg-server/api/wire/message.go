@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"time"
 
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"gopkg.in/guregu/null.v4"
 )
 
@@ -63,6 +64,28 @@ func (req SavedMessagesImportRequestWire) Validate() error {
 	return nil
 }
 
+type SavedMessageVariantWire struct {
+	Locale    string          `json:"locale"`
+	Data      json.RawMessage `json:"data"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type SavedMessageVariantListResponseWire APIResponse[[]SavedMessageVariantWire]
+
+type SavedMessageVariantUpdateRequestWire struct {
+	Data json.RawMessage `json:"data"`
+}
+
+func (req SavedMessageVariantUpdateRequestWire) Validate() error {
+	return validation.ValidateStruct(&req,
+		validation.Field(&req.Data, validation.Required),
+	)
+}
+
+type SavedMessageVariantUpdateResponseWire APIResponse[SavedMessageVariantWire]
+
+type SavedMessageVariantDeleteResponseWire APIResponse[struct{}]
+
 type MessageSendToWebhookRequestWire struct {
 	WebhookType  string                   `json:"webhook_type"`
 	WebhookID    string                   `json:"webhook_id"`
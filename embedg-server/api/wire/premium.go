@@ -19,9 +19,11 @@ type GetPremiumPlanFeaturesResponseDataWire struct {
 	IsPremium                 bool  `json:"is_premium"`
 	MaxImageUploadSize        int   `json:"max_image_upload_size"`
 	MaxScheduledMessages      int   `json:"max_scheduled_messages"`
+	MaxEventTriggers          int   `json:"max_event_triggers"`
 	PeriodicScheduledMessages bool  `json:"periodic_scheduled_messages"`
 	MaxTemplateOps            int   `json:"max_template_ops"`
 	MaxKVKeys                 int   `json:"max_kv_keys"`
+	HTTPRequests              bool  `json:"http_requests"`
 }
 
 type GetPremiumPlanFeaturesResponseWire APIResponse[GetPremiumPlanFeaturesResponseDataWire]
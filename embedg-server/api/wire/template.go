@@ -0,0 +1,170 @@
+package wire
+
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gopkg.in/guregu/null.v4"
+)
+
+type TemplateLintRequestWire struct {
+	Data json.RawMessage `json:"data"`
+}
+
+func (req TemplateLintRequestWire) Validate() error {
+	return nil
+}
+
+type TemplateDiagnosticWire struct {
+	Path        string   `json:"path"`
+	Line        int      `json:"line"`
+	Column      int      `json:"column"`
+	Message     string   `json:"message"`
+	Suggestions []string `json:"suggestions"`
+}
+
+type TemplateLintResponseDataWire struct {
+	Valid       bool                     `json:"valid"`
+	Diagnostics []TemplateDiagnosticWire `json:"diagnostics"`
+}
+
+type TemplateLintResponseWire APIResponse[TemplateLintResponseDataWire]
+
+type TemplatePreviewUserWire struct {
+	ID         string      `json:"id"`
+	Username   string      `json:"username"`
+	GlobalName null.String `json:"global_name"`
+	Avatar     null.String `json:"avatar"`
+	Nick       null.String `json:"nick"`
+	Roles      []string    `json:"roles"`
+}
+
+func (req TemplatePreviewUserWire) Validate() error {
+	return validation.ValidateStruct(&req,
+		validation.Field(&req.ID, validation.Required),
+		validation.Field(&req.Username, validation.Required, validation.Length(1, 32)),
+		validation.Field(&req.Roles, validation.Length(0, 250)),
+	)
+}
+
+type TemplatePreviewCommandWire struct {
+	Name    string                 `json:"name"`
+	Options map[string]interface{} `json:"options"`
+}
+
+func (req TemplatePreviewCommandWire) Validate() error {
+	return validation.ValidateStruct(&req,
+		validation.Field(&req.Name, validation.Required, validation.Length(1, 32)),
+		validation.Field(&req.Options, validation.Length(0, 25)),
+	)
+}
+
+type TemplatePreviewRequestWire struct {
+	Data      json.RawMessage             `json:"data"`
+	ChannelID null.String                 `json:"channel_id"`
+	User      *TemplatePreviewUserWire    `json:"user"`
+	Command   *TemplatePreviewCommandWire `json:"command"`
+	KV        map[string]string           `json:"kv"`
+	Locale    null.String                 `json:"locale"`
+}
+
+func (req TemplatePreviewRequestWire) Validate() error {
+	return validation.ValidateStruct(&req,
+		validation.Field(&req.User),
+		validation.Field(&req.Command),
+		validation.Field(&req.KV, validation.Length(0, 100)),
+		validation.Field(&req.Locale, validation.Match(localeRegex)),
+	)
+}
+
+type TemplatePreviewResponseDataWire struct {
+	Data  json.RawMessage `json:"data"`
+	Error null.String     `json:"error"`
+}
+
+type TemplatePreviewResponseWire APIResponse[TemplatePreviewResponseDataWire]
+
+var snippetNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,32}$`)
+
+// IsValidSnippetName returns whether the name can be used for a template snippet.
+func IsValidSnippetName(name string) bool {
+	return snippetNameRegex.MatchString(name)
+}
+
+type TemplateSnippetWire struct {
+	Name      string    `json:"name"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TemplateSnippetListResponseWire APIResponse[[]TemplateSnippetWire]
+
+type TemplateSnippetUpdateRequestWire struct {
+	Content string `json:"content"`
+}
+
+func (req TemplateSnippetUpdateRequestWire) Validate() error {
+	return validation.ValidateStruct(&req,
+		validation.Field(&req.Content, validation.Required, validation.Length(1, 16*1024)),
+	)
+}
+
+type TemplateSnippetUpdateResponseWire APIResponse[TemplateSnippetWire]
+
+type TemplateSnippetDeleteResponseWire APIResponse[struct{}]
+
+var httpHostRegex = regexp.MustCompile(`^(\*\.)?([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)*[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// IsValidHTTPHost returns whether the host can be added to the HTTP allowlist, it must be lowercase.
+func IsValidHTTPHost(host string) bool {
+	return len(host) <= 253 && httpHostRegex.MatchString(host)
+}
+
+type TemplateHTTPHostWire struct {
+	Host      string    `json:"host"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TemplateHTTPHostListResponseWire APIResponse[[]TemplateHTTPHostWire]
+
+type TemplateHTTPHostCreateRequestWire struct {
+	Host string `json:"host"`
+}
+
+func (req TemplateHTTPHostCreateRequestWire) Validate() error {
+	return validation.ValidateStruct(&req,
+		validation.Field(&req.Host, validation.Required, validation.Length(1, 253)),
+	)
+}
+
+type TemplateHTTPHostCreateResponseWire APIResponse[TemplateHTTPHostWire]
+
+type TemplateHTTPHostDeleteResponseWire APIResponse[struct{}]
+
+type TemplateVariablesConvertRequestWire struct {
+	Apply bool `json:"apply"`
+}
+
+func (req TemplateVariablesConvertRequestWire) Validate() error {
+	return nil
+}
+
+type TemplateVariablesChangeWire struct {
+	Type        string          `json:"type"`
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Converted   int             `json:"converted"`
+	Unsupported []string        `json:"unsupported"`
+	Data        json.RawMessage `json:"data"`
+}
+
+type TemplateVariablesConvertResponseDataWire struct {
+	Applied   bool                          `json:"applied"`
+	Converted int                           `json:"converted"`
+	Changes   []TemplateVariablesChangeWire `json:"changes"`
+}
+
+type TemplateVariablesConvertResponseWire APIResponse[TemplateVariablesConvertResponseDataWire]
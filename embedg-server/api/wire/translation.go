@@ -0,0 +1,54 @@
+package wire
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const maxTranslationsPerLocale = 500
+const maxTranslationKeyLength = 100
+const maxTranslationValueLength = 2000
+
+// localeRegex matches the locales that Discord supports like "de", "en-US" and "es-419".
+var localeRegex = regexp.MustCompile(`^[a-z]{2,3}(-([A-Z]{2}|[0-9]{3}))?$`)
+
+// IsValidLocale returns whether the locale has the format of a Discord locale.
+func IsValidLocale(locale string) bool {
+	return localeRegex.MatchString(locale)
+}
+
+type TranslationWire struct {
+	Locale    string    `json:"locale"`
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TranslationListResponseWire APIResponse[[]TranslationWire]
+
+type TranslationsUpdateRequestWire struct {
+	Translations map[string]string `json:"translations"`
+}
+
+func (req TranslationsUpdateRequestWire) Validate() error {
+	return validation.ValidateStruct(&req,
+		validation.Field(&req.Translations, validation.Length(0, maxTranslationsPerLocale), validation.By(func(value interface{}) error {
+			for key, value := range req.Translations {
+				if key == "" || len(key) > maxTranslationKeyLength {
+					return fmt.Errorf("keys must be between 1 and %d characters long", maxTranslationKeyLength)
+				}
+				if len(value) > maxTranslationValueLength {
+					return fmt.Errorf("value of %s must not be longer than %d characters", key, maxTranslationValueLength)
+				}
+			}
+			return nil
+		})),
+	)
+}
+
+type TranslationsUpdateResponseWire APIResponse[[]TranslationWire]
+
+type TranslationsDeleteResponseWire APIResponse[struct{}]
@@ -21,7 +21,7 @@ func (b *Bot) SendMessageToChannel(ctx context.Context, channelID string, params
 	}
 
 	useCustomBot := false
-	customBot, err := b.pg.Q.GetCustomBotByGuildID(ctx, channel.GuildID)
+	customBot, err := b.pg.GetCustomBotByGuildID(ctx, channel.GuildID)
 	if err != nil {
 		if err != sql.ErrNoRows {
 			log.Error().Err(err).Msg("failed to get custom bot for message username and avatar")
@@ -103,7 +103,7 @@ func (b *Bot) EditMessageInChannel(ctx context.Context, channelID string, messag
 	}
 
 	useCustomBot := false
-	customBot, err := b.pg.Q.GetCustomBotByGuildID(ctx, channel.GuildID)
+	customBot, err := b.pg.GetCustomBotByGuildID(ctx, channel.GuildID)
 	if err != nil {
 		if err != sql.ErrNoRows {
 			log.Error().Err(err).Msg("failed to get custom bot for message username and avatar")
@@ -196,7 +196,7 @@ func (b *Bot) FindWebhookForChannel(ctx context.Context, channelID string) (*dis
 		}
 	}
 
-	customBot, err := b.pg.Q.GetCustomBotByGuildID(ctx, channel.GuildID)
+	customBot, err := b.pg.GetCustomBotByGuildID(ctx, channel.GuildID)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("Failed to get custom bot: %w", err)
 	}
@@ -270,7 +270,7 @@ func (b *Bot) GetWebhookForChannel(ctx context.Context, channelID string, webhoo
 	// We have found the webhook, but it belongs to another application
 	// so let's try with the custom bot session if any
 	if webhook != nil && webhook.Token == "" {
-		customBot, err := b.pg.Q.GetCustomBotByGuildID(ctx, channel.GuildID)
+		customBot, err := b.pg.GetCustomBotByGuildID(ctx, channel.GuildID)
 		if err != nil && err != sql.ErrNoRows {
 			return nil, fmt.Errorf("Failed to get custom bot: %w", err)
 		}
@@ -308,7 +308,7 @@ func (b *Bot) GetWebhookForChannel(ctx context.Context, channelID string, webhoo
 // GetSessionForGuild returns the session for the given guild.
 // If a custom bot is configured for the guild, the token of the custom bot will be used to create the session.
 func (b *Bot) GetSessionForGuild(ctx context.Context, guildId string) (*discordgo.Session, error) {
-	customBot, err := b.pg.Q.GetCustomBotByGuildID(ctx, guildId)
+	customBot, err := b.pg.GetCustomBotByGuildID(ctx, guildId)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("Failed to get custom bot: %w", err)
 	}
@@ -30,4 +30,7 @@ func setupDefaults() {
 
 	// CDN defaults
 	v.SetDefault("cdn.public_url", "http://localhost:8080/cdn")
+
+	// Template defaults
+	v.SetDefault("templates.http.allow_private_networks", false)
 }
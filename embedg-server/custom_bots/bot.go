@@ -1,16 +1,26 @@
 package custom_bots
 
 import (
+	"errors"
 	"fmt"
+	"sync/atomic"
 
+	"github.com/gorilla/websocket"
 	"github.com/merlinfuchs/discordgo"
 	"github.com/rs/zerolog/log"
 	"gopkg.in/guregu/null.v4"
 )
 
+// customBotIntents are the intents that event triggers need, boosts are received as system messages.
+const customBotIntents = discordgo.IntentGuildMembers | discordgo.IntentGuildMessages
+
 type CustomBot struct {
+	ID       string
+	GuildID  string
 	Presence CustomBotPresence `json:"status"`
 	Session  *discordgo.Session
+
+	reconnecting atomic.Bool
 }
 
 func NewCustomBot(token string, presence CustomBotPresence) (*CustomBot, error) {
@@ -20,7 +30,6 @@ func NewCustomBot(token string, presence CustomBotPresence) (*CustomBot, error)
 	}
 
 	session.StateEnabled = false
-	session.Identify.Intents = 0
 	session.Identify.Presence = discordgo.GatewayStatusUpdate{
 		Status: presence.Status,
 		Game:   presence.Activity(),
@@ -28,19 +37,43 @@ func NewCustomBot(token string, presence CustomBotPresence) (*CustomBot, error)
 	session.SyncEvents = true
 	session.ShouldReconnectOnError = false
 
-	err = session.Open()
-	if err != nil {
-		return nil, fmt.Errorf("failed to open session: %w", err)
-	}
-
 	bot := &CustomBot{
 		Presence: presence,
 		Session:  session,
 	}
 
+	err = bot.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session: %w", err)
+	}
+
 	return bot, nil
 }
 
+// Open connects the session with the intents that event triggers need.
+// Interactions don't need any intents, so when the Server Members Intent isn't enabled for the application,
+// the session is connected without intents and the custom bot keeps working without event triggers.
+func (b *CustomBot) Open() error {
+	b.Session.Identify.Intents = customBotIntents
+
+	err := b.Session.Open()
+	if isDisallowedIntentsError(err) {
+		b.Session.Identify.Intents = 0
+		err = b.Session.Open()
+	}
+	return err
+}
+
+// HasEventIntents returns whether the session receives the events that event triggers need.
+func (b *CustomBot) HasEventIntents() bool {
+	return b.Session.Identify.Intents&customBotIntents == customBotIntents
+}
+
+func isDisallowedIntentsError(err error) bool {
+	var wsErr *websocket.CloseError
+	return errors.As(err, &wsErr) && wsErr.Code == 4014
+}
+
 func (b *CustomBot) UpdatePresence(p CustomBotPresence) {
 	if b.Session == nil {
 		return
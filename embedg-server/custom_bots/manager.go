@@ -2,7 +2,10 @@ package custom_bots
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -10,111 +13,594 @@ import (
 	"github.com/merlinfuchs/embed-generator/embedg-server/actions/handler"
 	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres"
 	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/merlinfuchs/embed-generator/embedg-server/model"
+	"github.com/merlinfuchs/embed-generator/embedg-server/util"
 	"github.com/rs/zerolog/log"
 	"gopkg.in/guregu/null.v4"
 )
 
+const gatewayTaskInterval = 30 * time.Second
+
+// customBotLeaseTTL is how long an instance owns a custom bot without renewing the lease.
+// When an instance dies, the other instances take over its custom bots once the leases have expired.
+const customBotLeaseTTL = 3 * gatewayTaskInterval
+
+// leaseRenewInterval is how often the leases are renewed while a sync is connecting custom bots.
+// Opening a session blocks until Discord has accepted it, so connecting many custom bots can take longer than the lease TTL.
+const leaseRenewInterval = gatewayTaskInterval
+
+// maxConnectsPerSync limits how long a sync blocks changes and failures, the remaining custom bots are connected by the next syncs.
+const maxConnectsPerSync = 50
+
+// gatewayInstanceTTL is how long an instance counts towards the distribution of custom bots without a heartbeat.
+const gatewayInstanceTTL = 3 * gatewayTaskInterval
+
+const maxReconnectAttempts = 8
+const minReconnectBackoff = 1 * time.Second
+const maxReconnectBackoff = 2 * time.Minute
+
+const shutdownTimeout = 10 * time.Second
+
+// EventTriggerHandler runs the event triggers of a guild for the events that its custom bot receives.
+// Only custom bots receive member events, the main bot doesn't request the privileged members intent.
+type EventTriggerHandler interface {
+	HandleMemberEvent(s *discordgo.Session, event string, guildID string, member *discordgo.Member)
+}
+
+// CustomBotManager connects custom bots to the gateway.
+// When multiple instances are running, each custom bot is only connected by the instance that holds its lease.
+// Every instance holds at most its fair share of the custom bots, so bots are rebalanced when an instance joins.
+//
+// All changes to the connected bots are made by a single goroutine which reacts to changes of custom bots,
+// failed reconnects and a periodic sync that renews the leases.
 type CustomBotManager struct {
+	sync.Mutex
 	pg            *postgres.PostgresStore
 	actionHandler *handler.ActionHandler
+	eventTriggers EventTriggerHandler
+	instanceID    string
+	startedAt     time.Time
 	bots          map[string]*CustomBot
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	done     chan struct{}
+	failures chan *CustomBot
 }
 
-func NewCustomBotManager(pg *postgres.PostgresStore, actionHandler *handler.ActionHandler) *CustomBotManager {
+func NewCustomBotManager(
+	pg *postgres.PostgresStore,
+	actionHandler *handler.ActionHandler,
+	eventTriggers EventTriggerHandler,
+) *CustomBotManager {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	m := &CustomBotManager{
 		pg:            pg,
 		actionHandler: actionHandler,
+		eventTriggers: eventTriggers,
+		instanceID:    util.UniqueID(),
+		startedAt:     time.Now().UTC(),
 		bots:          make(map[string]*CustomBot),
+
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		failures: make(chan *CustomBot),
 	}
 
-	go m.lazyCustomBotGatewayTask()
+	go m.run()
 
 	return m
 }
 
-func (m *CustomBotManager) lazyCustomBotGatewayTask() {
+// Close disconnects all custom bots and releases their leases so other instances can take them over immediately.
+func (m *CustomBotManager) Close() {
+	m.cancel()
+	<-m.done
+}
+
+func (m *CustomBotManager) run() {
+	defer close(m.done)
+
+	changes, err := m.pg.ListenCustomBotChanges(m.ctx)
+	if err != nil {
+		// Changes are still picked up by the periodic sync
+		log.Error().Err(err).Msg("Failed to listen for custom bot changes")
+	}
+
+	ticker := time.NewTicker(gatewayTaskInterval)
+	defer ticker.Stop()
+
+	m.sync(m.ctx)
+
 	for {
-		time.Sleep(30 * time.Second)
+		select {
+		case <-m.ctx.Done():
+			m.shutdown()
+			return
+		case <-ticker.C:
+			m.sync(m.ctx)
+		case guildID, ok := <-changes:
+			if !ok {
+				changes = nil
+				continue
+			}
 
-		customBots, err := m.pg.Q.GetCustomBots(context.Background())
-		if err != nil {
-			log.Error().Err(err).Msg("Failed to retrieve custom bots")
+			if guildID == "" {
+				m.sync(m.ctx)
+			} else if err := m.syncGuild(m.ctx, guildID); err != nil {
+				log.Error().Err(err).Str("guild_id", guildID).Msg("Failed to sync custom bot")
+			}
+		case bot := <-m.failures:
+			if m.getBot(bot.ID) == bot {
+				m.releaseBot(m.ctx, bot.ID, disconnectReasonReconnectFailed)
+			}
+		}
+	}
+}
+
+func (m *CustomBotManager) sync(ctx context.Context) {
+	if err := m.syncCustomBots(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to sync custom bots")
+	}
+}
+
+func (m *CustomBotManager) syncCustomBots(ctx context.Context) error {
+	if err := m.heartbeat(ctx, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	instances, err := m.pg.Q.CountGatewayInstances(ctx)
+	if err != nil {
+		return err
+	}
+
+	customBots, err := m.pg.GetCustomBots(ctx)
+	if err != nil {
+		return err
+	}
+
+	eligible := make([]pgmodel.CustomBot, 0, len(customBots))
+	for _, customBot := range customBots {
+		if !customBot.TokenInvalid {
+			eligible = append(eligible, customBot)
+		}
+	}
+
+	// Instances try to acquire the custom bots in different orders so they don't all compete for the same leases
+	rand.Shuffle(len(eligible), func(i, j int) {
+		eligible[i], eligible[j] = eligible[j], eligible[i]
+	})
+
+	if err := m.renewLeases(ctx); err != nil {
+		return err
+	}
+	renewedAt := time.Now()
+
+	target := len(eligible)
+	if instances > 1 {
+		target = (len(eligible) + int(instances) - 1) / int(instances)
+	}
+
+	eligibleIDs := make(map[string]bool, len(eligible))
+	held := 0
+	for _, customBot := range eligible {
+		eligibleIDs[customBot.ID] = true
+
+		bot := m.getBot(customBot.ID)
+		if bot == nil {
 			continue
 		}
 
-		newBots := 0
-		for _, customBot := range customBots {
-			if customBot.TokenInvalid {
-				continue
-			}
+		if held >= target {
+			m.releaseBot(ctx, customBot.ID, disconnectReasonRebalanced)
+			continue
+		}
+		held++
+
+		presence := customBotPresence(customBot)
+		if bot.Presence != presence {
+			bot.UpdatePresence(presence)
+		}
+
+		m.recordLatency(ctx, bot)
+	}
+
+	// Sessions of custom bots that were disabled or whose token changed
+	for _, id := range m.botIDs() {
+		if !eligibleIDs[id] {
+			m.releaseBot(ctx, id, disconnectReasonDisabled)
+		}
+	}
+
+	newBots := 0
+	for _, customBot := range eligible {
+		if held >= target || newBots >= maxConnectsPerSync {
+			break
+		}
+		if m.getBot(customBot.ID) != nil {
+			continue
+		}
 
-			presence := CustomBotPresence{
-				Status:        customBot.GatewayStatus,
-				ActivityType:  null.NewInt(int64(customBot.GatewayActivityType.Int16), customBot.GatewayActivityType.Valid),
-				ActivityName:  null.String{NullString: customBot.GatewayActivityName},
-				ActivityState: null.String{NullString: customBot.GatewayActivityState},
-				ActivityURL:   null.String{NullString: customBot.GatewayActivityUrl},
+		// Keep the leases of the custom bots that were connected earlier in this sync
+		if time.Since(renewedAt) >= leaseRenewInterval {
+			if err := m.renewLeases(ctx); err != nil {
+				return err
 			}
+			renewedAt = time.Now()
+		}
 
-			if bot, ok := m.bots[customBot.ID]; ok {
-				if bot.Presence != presence {
-					bot.UpdatePresence(presence)
-				}
-				continue
+		connected, err := m.acquireAndConnect(ctx, customBot)
+		if err != nil {
+			log.Error().Err(err).Str("custom_bot_id", customBot.ID).Msg("Failed to connect custom bot")
+			continue
+		}
+		if connected {
+			held++
+			newBots++
+		}
+	}
+
+	if newBots > 0 {
+		log.Info().Msgf("%d custom bots connected to the gateway", newBots)
+	}
+
+	return nil
+}
+
+// syncGuild applies the changes to the custom bot of the guild immediately.
+// The custom bot is connected by this instance if no other instance holds its lease, the next sync rebalances it if needed.
+func (m *CustomBotManager) syncGuild(ctx context.Context, guildID string) error {
+	existing := m.getBotForGuild(guildID)
+
+	customBot, err := m.pg.GetCustomBotByGuildID(ctx, guildID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			if existing != nil {
+				m.releaseBot(ctx, existing.ID, disconnectReasonDisabled)
 			}
+			return nil
+		}
+		return err
+	}
 
-			bot, err := NewCustomBot(customBot.Token, presence)
-			if err != nil {
-				var wsErr *websocket.CloseError
-				if errors.As(err, &wsErr) && wsErr.Code == 4004 {
-					_, err := m.pg.Q.UpdateCustomBotTokenInvalid(context.Background(), pgmodel.UpdateCustomBotTokenInvalidParams{
-						GuildID:      customBot.GuildID,
-						TokenInvalid: true,
-					})
-					if err != nil {
-						log.Error().Err(err).Msg("Failed to set custom bot token invalid")
-					}
-					continue
-				}
-
-				log.Error().Err(err).Msg("Failed to create custom bot")
-				continue
+	// A new token results in a new ID for the custom bot
+	if existing != nil && (existing.ID != customBot.ID || customBot.TokenInvalid) {
+		m.releaseBot(ctx, existing.ID, disconnectReasonDisabled)
+		existing = nil
+	}
+
+	if customBot.TokenInvalid {
+		return nil
+	}
+
+	// Reconnect custom bots that run without intents, so saving the settings picks up a newly enabled Server Members Intent
+	if existing != nil && !existing.HasEventIntents() {
+		m.releaseBot(ctx, existing.ID, disconnectReasonIntents)
+		existing = nil
+	}
+
+	if existing != nil {
+		presence := customBotPresence(customBot)
+		if existing.Presence != presence {
+			existing.UpdatePresence(presence)
+		}
+		return nil
+	}
+
+	_, err = m.acquireAndConnect(ctx, customBot)
+	return err
+}
+
+// heartbeat marks this instance as alive and removes the instances that stopped sending heartbeats.
+func (m *CustomBotManager) heartbeat(ctx context.Context, now time.Time) error {
+	err := m.pg.Q.UpsertGatewayInstance(ctx, pgmodel.UpsertGatewayInstanceParams{
+		ID:          m.instanceID,
+		StartedAt:   m.startedAt,
+		HeartbeatAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = m.pg.Q.DeleteStaleGatewayInstances(ctx, now.Add(-gatewayInstanceTTL))
+	return err
+}
+
+// renewLeases extends the leases of all connected custom bots.
+// Custom bots whose lease was lost, because it expired or the custom bot was deleted, are disconnected.
+func (m *CustomBotManager) renewLeases(ctx context.Context) error {
+	ids := m.botIDs()
+	if len(ids) == 0 {
+		return nil
+	}
+
+	renewed, err := m.pg.Q.RenewCustomBotLeases(ctx, pgmodel.RenewCustomBotLeasesParams{
+		TtlSeconds:   int32(customBotLeaseTTL.Seconds()),
+		InstanceID:   m.instanceID,
+		CustomBotIds: ids,
+	})
+	if err != nil {
+		return err
+	}
+
+	renewedIDs := make(map[string]bool, len(renewed))
+	for _, id := range renewed {
+		renewedIDs[id] = true
+	}
+
+	for _, id := range ids {
+		if !renewedIDs[id] {
+			log.Warn().Str("custom_bot_id", id).Msg("Lost lease for custom bot, disconnecting")
+			m.disconnectBot(id, disconnectReasonLeaseLost)
+		}
+	}
+
+	return nil
+}
+
+// acquireAndConnect connects the custom bot if the lease could be acquired.
+// The lease times come from the database clock, so clock skew between instances can't break the lease.
+func (m *CustomBotManager) acquireAndConnect(ctx context.Context, customBot pgmodel.CustomBot) (bool, error) {
+	acquired, err := m.pg.Q.AcquireCustomBotLease(ctx, pgmodel.AcquireCustomBotLeaseParams{
+		CustomBotID: customBot.ID,
+		InstanceID:  m.instanceID,
+		TtlSeconds:  int32(customBotLeaseTTL.Seconds()),
+	})
+	if err != nil {
+		return false, err
+	}
+	if acquired == 0 {
+		return false, nil
+	}
+
+	if err := m.connectBot(customBot); err != nil {
+		m.releaseLease(ctx, customBot.ID)
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *CustomBotManager) connectBot(customBot pgmodel.CustomBot) error {
+	bot, err := NewCustomBot(customBot.Token, customBotPresence(customBot))
+	if err != nil {
+		if isInvalidTokenError(err) {
+			m.markTokenInvalid(customBot.GuildID)
+		}
+		return err
+	}
+
+	bot.ID = customBot.ID
+	bot.GuildID = customBot.GuildID
+
+	bot.Session.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
+		m.recordConnected(bot)
+	})
+
+	bot.Session.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		m.recordInteraction(customBot.ID)
+
+		if err := m.pg.Q.SetCustomBotHandledFirstInteraction(context.Background(), customBot.ID); err != nil {
+			log.Error().Err(err).Msg("Failed to set custom bot handled first interaction")
+		}
+
+		gi := &handler.GatewayInteraction{
+			Session: s,
+			Inner:   i.Interaction,
+		}
+
+		if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+			if err := m.actionHandler.HandleAutocompleteInteraction(s, gi); err != nil {
+				log.Error().Err(err).Msg("Failed to handle autocomplete interaction from custom bot gateway")
 			}
+			return
+		}
 
-			bot.Session.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-				err = m.pg.Q.SetCustomBotHandledFirstInteraction(context.Background(), customBot.ID)
-				if err != nil {
-					log.Error().Err(err).Msg("Failed to set custom bot handled first interaction")
-				}
-
-				err := m.actionHandler.HandleActionInteraction(s, &handler.GatewayInteraction{
-					Session: s,
-					Inner:   i.Interaction,
-				})
-				if err != nil {
-					log.Error().Err(err).Msg("Failed to handle action interaction from custom bot gateway")
-				}
-			})
-
-			bot.Session.AddHandler(func(s *discordgo.Session, i *discordgo.Disconnect) {
-				// Normally DiscordGo would handle reconnection, but it doesn't have any logic to detect a token reset and will just keep trying to reconnect with the old token
-				// We only make a single reconnect attempt, if that fails we hand it off to the background task to spawn a new session
-				// The background task will detect if the token is invalid and mark the custom bot accordingly
-
-				err := s.Open()
-				if err != nil {
-					log.Error().Err(err).Msg("Failed to reconnect custom bot")
-
-					delete(m.bots, customBot.ID)
-				}
-			})
-
-			m.bots[customBot.ID] = bot
-			newBots++
+		err := m.actionHandler.HandleActionInteraction(s, gi)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to handle action interaction from custom bot gateway")
+		}
+	})
+
+	// The session handles events synchronously, so event triggers must not block it
+	bot.Session.AddHandler(func(s *discordgo.Session, g *discordgo.GuildMemberAdd) {
+		if g.GuildID == customBot.GuildID {
+			go m.eventTriggers.HandleMemberEvent(s, model.EventTriggerMemberJoin, g.GuildID, g.Member)
+		}
+	})
+
+	bot.Session.AddHandler(func(s *discordgo.Session, g *discordgo.GuildMemberRemove) {
+		if g.GuildID == customBot.GuildID {
+			go m.eventTriggers.HandleMemberEvent(s, model.EventTriggerMemberLeave, g.GuildID, g.Member)
+		}
+	})
+
+	bot.Session.AddHandler(func(s *discordgo.Session, msg *discordgo.MessageCreate) {
+		if member := util.BoostMember(msg.Message); member != nil && msg.GuildID == customBot.GuildID {
+			go m.eventTriggers.HandleMemberEvent(s, model.EventTriggerMemberBoost, msg.GuildID, member)
+		}
+	})
+
+	bot.Session.AddHandler(func(s *discordgo.Session, i *discordgo.Disconnect) {
+		// The session was closed on purpose because the custom bot was released
+		if m.getBot(customBot.ID) != bot {
+			return
+		}
+
+		m.recordDisconnected(customBot.ID, disconnectReasonConnectionLost)
+		go m.reconnect(bot)
+	})
+
+	m.Lock()
+	m.bots[customBot.ID] = bot
+	m.Unlock()
+
+	if !bot.HasEventIntents() {
+		log.Warn().
+			Str("custom_bot_id", customBot.ID).
+			Str("guild_id", customBot.GuildID).
+			Msg("Server Members Intent isn't enabled for custom bot, connected without event triggers")
+	}
+
+	// The session was opened before the handlers were added, so the first ready event is missed
+	m.recordConnected(bot)
+
+	return nil
+}
+
+// reconnect tries to reopen the session of the custom bot with exponential backoff.
+// DiscordGo doesn't detect a token reset and would keep trying to reconnect with the old token,
+// so we handle reconnects ourselves and hand the custom bot back to the manager when they keep failing.
+func (m *CustomBotManager) reconnect(bot *CustomBot) {
+	if !bot.reconnecting.CompareAndSwap(false, true) {
+		return
+	}
+	defer bot.reconnecting.Store(false)
+
+	for attempt := 0; attempt < maxReconnectAttempts; attempt++ {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-time.After(reconnectBackoff(attempt)):
+		}
+
+		if m.getBot(bot.ID) != bot {
+			return
 		}
 
-		if newBots > 0 {
-			log.Info().Msgf("%d custom bots connected to the gateway", newBots)
+		// Also picks up the Server Members Intent if it has been enabled in the meantime
+		err := bot.Open()
+		if err == nil {
+			m.recordReconnect(bot.ID)
+			return
 		}
+		if errors.Is(err, discordgo.ErrWSAlreadyOpen) {
+			return
+		}
+
+		if isInvalidTokenError(err) {
+			m.markTokenInvalid(bot.GuildID)
+			break
+		}
+
+		log.Warn().Err(err).Str("custom_bot_id", bot.ID).Int("attempt", attempt+1).Msg("Failed to reconnect custom bot")
+	}
+
+	select {
+	case m.failures <- bot:
+	case <-m.ctx.Done():
+	}
+}
+
+func reconnectBackoff(attempt int) time.Duration {
+	backoff := minReconnectBackoff << attempt
+	if backoff > maxReconnectBackoff {
+		backoff = maxReconnectBackoff
+	}
+
+	// Jitter prevents all custom bots from reconnecting at the same time after an outage
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)))
+}
+
+func isInvalidTokenError(err error) bool {
+	var wsErr *websocket.CloseError
+	return errors.As(err, &wsErr) && wsErr.Code == 4004
+}
+
+func (m *CustomBotManager) markTokenInvalid(guildID string) {
+	_, err := m.pg.Q.UpdateCustomBotTokenInvalid(context.Background(), pgmodel.UpdateCustomBotTokenInvalidParams{
+		GuildID:      guildID,
+		TokenInvalid: true,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to set custom bot token invalid")
+	}
+}
+
+// shutdown releases all custom bots and removes the instance so the remaining instances rebalance right away.
+func (m *CustomBotManager) shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	for _, id := range m.botIDs() {
+		m.releaseBot(ctx, id, disconnectReasonShutdown)
+	}
+
+	if err := m.pg.Q.DeleteGatewayInstance(ctx, m.instanceID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete gateway instance")
+	}
+}
+
+// releaseBot disconnects the custom bot and releases its lease so another instance can take it over.
+func (m *CustomBotManager) releaseBot(ctx context.Context, id string, reason string) {
+	m.disconnectBot(id, reason)
+	m.releaseLease(ctx, id)
+}
+
+func (m *CustomBotManager) releaseLease(ctx context.Context, id string) {
+	err := m.pg.Q.ReleaseCustomBotLease(ctx, pgmodel.ReleaseCustomBotLeaseParams{
+		CustomBotID: id,
+		InstanceID:  m.instanceID,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to release custom bot lease")
+	}
+}
+
+func (m *CustomBotManager) disconnectBot(id string, reason string) {
+	m.Lock()
+	bot, ok := m.bots[id]
+	delete(m.bots, id)
+	m.Unlock()
+
+	if !ok {
+		return
+	}
+
+	if err := bot.Session.Close(); err != nil {
+		log.Error().Err(err).Msg("Failed to close custom bot session")
+	}
+
+	m.recordDisconnected(id, reason)
+}
+
+func (m *CustomBotManager) getBot(id string) *CustomBot {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.bots[id]
+}
+
+func (m *CustomBotManager) getBotForGuild(guildID string) *CustomBot {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, bot := range m.bots {
+		if bot.GuildID == guildID {
+			return bot
+		}
+	}
+	return nil
+}
+
+func (m *CustomBotManager) botIDs() []string {
+	m.Lock()
+	defer m.Unlock()
+
+	ids := make([]string, 0, len(m.bots))
+	for id := range m.bots {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func customBotPresence(customBot pgmodel.CustomBot) CustomBotPresence {
+	return CustomBotPresence{
+		Status:        customBot.GatewayStatus,
+		ActivityType:  null.NewInt(int64(customBot.GatewayActivityType.Int16), customBot.GatewayActivityType.Valid),
+		ActivityName:  null.String{NullString: customBot.GatewayActivityName},
+		ActivityState: null.String{NullString: customBot.GatewayActivityState},
+		ActivityURL:   null.String{NullString: customBot.GatewayActivityUrl},
 	}
 }
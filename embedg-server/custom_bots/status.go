@@ -0,0 +1,99 @@
+package custom_bots
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/rs/zerolog/log"
+)
+
+const statusTimeout = 5 * time.Second
+
+// Reasons that are shown to the user when a custom bot isn't connected.
+const (
+	disconnectReasonConnectionLost  = "The connection to Discord was lost"
+	disconnectReasonReconnectFailed = "Reconnecting to Discord failed repeatedly"
+	disconnectReasonLeaseLost       = "Another instance took over the custom bot"
+	disconnectReasonRebalanced      = "The custom bot was moved to another instance"
+	disconnectReasonDisabled        = "The custom bot was disabled or its token was changed"
+	disconnectReasonShutdown        = "The instance was shut down"
+	disconnectReasonIntents         = "The custom bot was reconnected to check its intents"
+)
+
+// The status of the gateway session is stored in the database because the API might be served by another instance.
+// Failing to record it must never affect the session itself, so errors are only logged.
+
+func (m *CustomBotManager) recordConnected(bot *CustomBot) {
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+
+	err := m.pg.Q.SetCustomBotGatewayConnected(ctx, pgmodel.SetCustomBotGatewayConnectedParams{
+		CustomBotID:    bot.ID,
+		InstanceID:     sql.NullString{String: m.instanceID, Valid: true},
+		GatewayReadyAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		GatewayIntents: sql.NullInt32{Int32: int32(bot.Session.Identify.Intents), Valid: true},
+	})
+	if err != nil {
+		log.Error().Err(err).Str("custom_bot_id", bot.ID).Msg("Failed to record custom bot connected")
+	}
+}
+
+func (m *CustomBotManager) recordDisconnected(id string, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+
+	err := m.pg.Q.SetCustomBotGatewayDisconnected(ctx, pgmodel.SetCustomBotGatewayDisconnectedParams{
+		CustomBotID:             id,
+		InstanceID:              sql.NullString{String: m.instanceID, Valid: true},
+		GatewayDisconnectedAt:   sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		GatewayDisconnectReason: sql.NullString{String: reason, Valid: true},
+	})
+	if err != nil {
+		log.Error().Err(err).Str("custom_bot_id", id).Msg("Failed to record custom bot disconnected")
+	}
+}
+
+func (m *CustomBotManager) recordReconnect(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+
+	err := m.pg.Q.IncrementCustomBotGatewayReconnects(ctx, pgmodel.IncrementCustomBotGatewayReconnectsParams{
+		CustomBotID: id,
+		UpdatedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		log.Error().Err(err).Str("custom_bot_id", id).Msg("Failed to record custom bot reconnect")
+	}
+}
+
+func (m *CustomBotManager) recordLatency(ctx context.Context, bot *CustomBot) {
+	latency := bot.Session.HeartbeatLatency()
+	if latency <= 0 {
+		// No heartbeat has been acknowledged yet
+		return
+	}
+
+	err := m.pg.Q.SetCustomBotGatewayLatency(ctx, pgmodel.SetCustomBotGatewayLatencyParams{
+		CustomBotID:      bot.ID,
+		GatewayLatencyMs: sql.NullInt32{Int32: int32(latency.Milliseconds()), Valid: true},
+		UpdatedAt:        time.Now().UTC(),
+	})
+	if err != nil {
+		log.Error().Err(err).Str("custom_bot_id", bot.ID).Msg("Failed to record custom bot latency")
+	}
+}
+
+func (m *CustomBotManager) recordInteraction(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+
+	err := m.pg.Q.SetCustomBotLastInteraction(ctx, pgmodel.SetCustomBotLastInteractionParams{
+		CustomBotID:       id,
+		LastInteractionAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+	})
+	if err != nil {
+		log.Error().Err(err).Str("custom_bot_id", id).Msg("Failed to record custom bot interaction")
+	}
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+const customBotChangesChannel = "custom_bot_changes"
+
+// NotifyCustomBotChanged tells all instances that the custom bot of the guild was configured, updated or disabled.
+func (s *PostgresStore) NotifyCustomBotChanged(ctx context.Context, guildID string) error {
+	_, err := s.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", customBotChangesChannel, guildID)
+	return err
+}
+
+// ListenCustomBotChanges returns a channel that receives the guild ID whenever a custom bot changed.
+// It receives an empty string after the connection was re-established because notifications may have been missed.
+// The channel is closed once the context is done.
+func (s *PostgresStore) ListenCustomBotChanges(ctx context.Context) (<-chan string, error) {
+	listener := pq.NewListener(BuildConnectionDSN(), 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Error().Err(err).Msg("Custom bot changes listener connection failed")
+		}
+	})
+
+	if err := listener.Listen(customBotChangesChannel); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	changes := make(chan string)
+	go func() {
+		defer close(changes)
+		defer listener.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case n := <-listener.Notify:
+				guildID := ""
+				if n != nil {
+					guildID = n.Extra
+				}
+
+				select {
+				case changes <- guildID:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return changes, nil
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+)
+
+// The token of custom bots is encrypted at rest, these methods should be used instead of the queries
+// whenever the token is read or written.
+
+func (s *PostgresStore) GetCustomBot(ctx context.Context, id string) (pgmodel.CustomBot, error) {
+	row, err := s.Q.GetCustomBot(ctx, id)
+	if err != nil {
+		return pgmodel.CustomBot{}, err
+	}
+	return s.decryptCustomBot(row)
+}
+
+func (s *PostgresStore) GetCustomBotByGuildID(ctx context.Context, guildID string) (pgmodel.CustomBot, error) {
+	row, err := s.Q.GetCustomBotByGuildID(ctx, guildID)
+	if err != nil {
+		return pgmodel.CustomBot{}, err
+	}
+	return s.decryptCustomBot(row)
+}
+
+func (s *PostgresStore) GetCustomBots(ctx context.Context) ([]pgmodel.CustomBot, error) {
+	rows, err := s.Q.GetCustomBots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, row := range rows {
+		rows[i], err = s.decryptCustomBot(row)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+func (s *PostgresStore) UpsertCustomBot(ctx context.Context, params pgmodel.UpsertCustomBotParams) (pgmodel.CustomBot, error) {
+	token, err := s.keyring.Encrypt(params.Token)
+	if err != nil {
+		return pgmodel.CustomBot{}, fmt.Errorf("failed to encrypt custom bot token: %w", err)
+	}
+	params.Token = token
+
+	row, err := s.Q.UpsertCustomBot(ctx, params)
+	if err != nil {
+		return pgmodel.CustomBot{}, err
+	}
+	return s.decryptCustomBot(row)
+}
+
+func (s *PostgresStore) UpdateCustomBotUser(ctx context.Context, params pgmodel.UpdateCustomBotUserParams) (pgmodel.CustomBot, error) {
+	row, err := s.Q.UpdateCustomBotUser(ctx, params)
+	if err != nil {
+		return pgmodel.CustomBot{}, err
+	}
+	return s.decryptCustomBot(row)
+}
+
+func (s *PostgresStore) decryptCustomBot(row pgmodel.CustomBot) (pgmodel.CustomBot, error) {
+	token, err := s.keyring.Decrypt(row.Token)
+	if err != nil {
+		return pgmodel.CustomBot{}, fmt.Errorf("failed to decrypt token of custom bot %s: %w", row.ID, err)
+	}
+	row.Token = token
+	return row, nil
+}
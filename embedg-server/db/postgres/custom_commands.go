@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+)
+
+// ImportCustomCommands atomically inserts and updates the given custom commands, either all of them are written or none.
+func (s *PostgresStore) ImportCustomCommands(
+	ctx context.Context,
+	inserts []pgmodel.InsertCustomCommandParams,
+	updates []pgmodel.UpdateCustomCommandParams,
+) ([]pgmodel.CustomCommand, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := s.Q.WithTx(tx)
+
+	res := make([]pgmodel.CustomCommand, 0, len(inserts)+len(updates))
+	for _, params := range updates {
+		row, err := q.UpdateCustomCommand(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, row)
+	}
+
+	for _, params := range inserts {
+		row, err := q.InsertCustomCommand(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, row)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return res, nil
+}
@@ -3,7 +3,12 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/lib/pq"
 
 	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
 	"github.com/merlinfuchs/embed-generator/embedg-server/model"
@@ -38,7 +43,27 @@ func (s *PostgresStore) SetKVEntry(ctx context.Context, entry model.KVEntry) err
 		CreatedAt: entry.CreatedAt,
 		UpdatedAt: entry.UpdatedAt,
 	})
-	return err
+	return kvEntryError(err)
+}
+
+func (s *PostgresStore) SetKVEntryExpiresAt(ctx context.Context, guildID string, key string, expiresAt null.Time) (model.KVEntry, error) {
+	row, err := s.Q.SetKVEntryExpiresAt(ctx, pgmodel.SetKVEntryExpiresAtParams{
+		Key:     key,
+		GuildID: guildID,
+		ExpiresAt: sql.NullTime{
+			Time:  expiresAt.Time,
+			Valid: expiresAt.Valid,
+		},
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.KVEntry{}, store.ErrNotFound
+		}
+		return model.KVEntry{}, err
+	}
+
+	return rowToKVEntry(row), nil
 }
 
 func (s *PostgresStore) IncreaseKVEntry(ctx context.Context, params model.KVEntryIncreaseParams) (model.KVEntry, error) {
@@ -73,7 +98,13 @@ func (s *PostgresStore) DeleteKVEntry(ctx context.Context, guildID string, key s
 		return model.KVEntry{}, err
 	}
 
-	return rowToKVEntry(row), nil
+	entry := rowToKVEntry(row)
+	if entry.IsExpired(time.Now().UTC()) {
+		// The entry was only waiting to be cleaned up, so it didn't exist from the perspective of the caller
+		return model.KVEntry{}, store.ErrNotFound
+	}
+
+	return entry, nil
 }
 
 func (s *PostgresStore) SearchKVEntries(ctx context.Context, guildID string, pattern string) ([]model.KVEntry, error) {
@@ -92,6 +123,94 @@ func (s *PostgresStore) SearchKVEntries(ctx context.Context, guildID string, pat
 
 	return entries, nil
 }
+
+// ListKVEntries returns a page of the entries that match the pattern sorted by key and the total number of matching entries.
+func (s *PostgresStore) ListKVEntries(ctx context.Context, guildID string, pattern string, limit int, offset int) ([]model.KVEntry, int, error) {
+	rows, err := s.Q.ListKVEntries(ctx, pgmodel.ListKVEntriesParams{
+		Key:         pattern,
+		GuildID:     guildID,
+		MaxEntries:  int32(limit),
+		SkipEntries: int32(offset),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.Q.CountMatchingKVEntries(ctx, pgmodel.CountMatchingKVEntriesParams{
+		Key:     pattern,
+		GuildID: guildID,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]model.KVEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = rowToKVEntry(row)
+	}
+
+	return entries, int(total), nil
+}
+
+// ImportKVEntries atomically sets the given entries of the guild, either all of them are written or none.
+// The keys of the entries must be unique. It returns the number of keys that didn't exist before and fails with
+// store.ErrTooManyKeys when they would exceed maxKeys.
+func (s *PostgresStore) ImportKVEntries(ctx context.Context, guildID string, entries []model.KVEntry, maxKeys int) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := s.Q.WithTx(tx)
+
+	keys := make([]string, len(entries))
+	for i, entry := range entries {
+		keys[i] = entry.Key
+	}
+
+	existing, err := q.CountExistingKVEntries(ctx, pgmodel.CountExistingKVEntriesParams{
+		GuildID: guildID,
+		Keys:    keys,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	created := len(entries) - int(existing)
+	if created > 0 {
+		count, err := q.CountKVEntries(ctx, guildID)
+		if err != nil {
+			return 0, err
+		}
+		if int(count)+created > maxKeys {
+			return 0, store.ErrTooManyKeys
+		}
+	}
+
+	for _, entry := range entries {
+		err := q.SetKVEntry(ctx, pgmodel.SetKVEntryParams{
+			Key:     entry.Key,
+			GuildID: guildID,
+			Value:   entry.Value,
+			ExpiresAt: sql.NullTime{
+				Time:  entry.ExpiresAt.Time,
+				Valid: entry.ExpiresAt.Valid,
+			},
+			CreatedAt: entry.CreatedAt,
+			UpdatedAt: entry.UpdatedAt,
+		})
+		if err != nil {
+			return 0, kvEntryError(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return created, nil
+}
+
 func (s *PostgresStore) CountKVEntries(ctx context.Context, guildID string) (int, error) {
 	count, err := s.Q.CountKVEntries(ctx, guildID)
 	if err != nil {
@@ -101,6 +220,140 @@ func (s *PostgresStore) CountKVEntries(ctx context.Context, guildID string) (int
 	return int(count), nil
 }
 
+func (s *PostgresStore) DeleteExpiredKVEntries(ctx context.Context, before time.Time, limit int) (int, error) {
+	count, err := s.Q.DeleteExpiredKVEntries(ctx, pgmodel.DeleteExpiredKVEntriesParams{
+		ExpiresAt: sql.NullTime{
+			Time:  before,
+			Valid: true,
+		},
+		Limit: int32(limit),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (s *PostgresStore) PushKVEntryList(ctx context.Context, params model.KVEntryCollectionParams) (model.KVEntry, error) {
+	row, err := s.Q.PushKVEntryList(ctx, pgmodel.PushKVEntryListParams{
+		Key:       params.Key,
+		GuildID:   params.GuildID,
+		Value:     string(params.Value),
+		CreatedAt: params.CreatedAt,
+		UpdatedAt: params.UpdatedAt,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// The upsert is skipped when the existing value isn't a list
+			return model.KVEntry{}, store.ErrWrongValueType
+		}
+		return model.KVEntry{}, kvEntryError(err)
+	}
+
+	return rowToKVEntry(row), nil
+}
+
+func (s *PostgresStore) AddKVEntrySetItem(ctx context.Context, params model.KVEntryCollectionParams) (model.KVEntry, bool, error) {
+	row, err := s.Q.AddKVEntrySetItem(ctx, pgmodel.AddKVEntrySetItemParams{
+		Key:       params.Key,
+		GuildID:   params.GuildID,
+		Value:     string(params.Value),
+		CreatedAt: params.CreatedAt,
+		UpdatedAt: params.UpdatedAt,
+	})
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return model.KVEntry{}, false, kvEntryError(err)
+		}
+
+		// The upsert is skipped when the item is already present or the existing value isn't a list
+		entry, err := s.GetKVEntry(ctx, params.GuildID, params.Key)
+		if err != nil {
+			return model.KVEntry{}, false, err
+		}
+		if !isJSONList(entry.Value) {
+			return model.KVEntry{}, false, store.ErrWrongValueType
+		}
+		return entry, false, nil
+	}
+
+	return rowToKVEntry(row), true, nil
+}
+
+func (s *PostgresStore) RemoveKVEntryListItem(ctx context.Context, guildID string, key string, item json.RawMessage) (model.KVEntry, error) {
+	row, err := s.Q.RemoveKVEntryListItem(ctx, pgmodel.RemoveKVEntryListItemParams{
+		Item:      item,
+		UpdatedAt: time.Now().UTC(),
+		Key:       key,
+		GuildID:   guildID,
+	})
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return model.KVEntry{}, kvEntryError(err)
+		}
+
+		// Distinguish between a missing entry and one that isn't a list
+		if _, err := s.GetKVEntry(ctx, guildID, key); err != nil {
+			return model.KVEntry{}, err
+		}
+		return model.KVEntry{}, store.ErrWrongValueType
+	}
+
+	return rowToKVEntry(row), nil
+}
+
+func (s *PostgresStore) KVEntryListContains(ctx context.Context, guildID string, key string, item json.RawMessage) (bool, error) {
+	contains, err := s.Q.KVEntryListContains(ctx, pgmodel.KVEntryListContainsParams{
+		Key:     key,
+		GuildID: guildID,
+		Item:    item,
+	})
+	if err != nil {
+		return false, kvEntryError(err)
+	}
+
+	return contains, nil
+}
+
+func (s *PostgresStore) SetKVEntryMapField(ctx context.Context, params model.KVEntryCollectionParams) (model.KVEntry, error) {
+	row, err := s.Q.SetKVEntryMapField(ctx, pgmodel.SetKVEntryMapFieldParams{
+		Key:       params.Key,
+		GuildID:   params.GuildID,
+		Value:     string(params.Value),
+		CreatedAt: params.CreatedAt,
+		UpdatedAt: params.UpdatedAt,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// The upsert is skipped when the existing value isn't a map
+			return model.KVEntry{}, store.ErrWrongValueType
+		}
+		return model.KVEntry{}, kvEntryError(err)
+	}
+
+	return rowToKVEntry(row), nil
+}
+
+// kvEntryError translates postgres errors caused by the stored value into store errors.
+func kvEntryError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "22P02": // invalid_text_representation: the existing value isn't valid JSON
+			return store.ErrWrongValueType
+		case "23514": // check_violation: kv_entries_value_length
+			return store.ErrValueTooLarge
+		}
+	}
+	return err
+}
+
+func isJSONList(value string) bool {
+	var list []json.RawMessage
+	return json.Unmarshal([]byte(value), &list) == nil && list != nil
+}
+
 func rowToKVEntry(row pgmodel.KvEntry) model.KVEntry {
 	return model.KVEntry{
 		Key:       row.Key,
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS kv_entries_expires_at_idx;
@@ -0,0 +1 @@
+ALTER TABLE kv_entries DROP CONSTRAINT IF EXISTS kv_entries_value_length;
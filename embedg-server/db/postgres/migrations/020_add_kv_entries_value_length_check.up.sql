@@ -0,0 +1 @@
+ALTER TABLE kv_entries ADD CONSTRAINT kv_entries_value_length CHECK (octet_length(value) <= 16384) NOT VALID;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS saved_message_variants;
+DROP TABLE IF EXISTS guild_translations;
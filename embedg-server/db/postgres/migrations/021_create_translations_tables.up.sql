@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS guild_translations (
+    guild_id TEXT NOT NULL,
+    locale TEXT NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (guild_id, locale, key)
+);
+
+CREATE TABLE IF NOT EXISTS saved_message_variants (
+    saved_message_id TEXT NOT NULL REFERENCES saved_messages (id) ON DELETE CASCADE,
+    locale TEXT NOT NULL,
+    data JSONB NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (saved_message_id, locale)
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS template_snippets (
+    guild_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (guild_id, name)
+);
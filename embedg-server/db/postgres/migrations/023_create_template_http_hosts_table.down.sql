@@ -0,0 +1 @@
+DROP TABLE IF EXISTS template_http_hosts;
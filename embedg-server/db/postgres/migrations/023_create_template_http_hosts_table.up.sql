@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS template_http_hosts (
+    guild_id TEXT NOT NULL,
+    host TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (guild_id, host)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS custom_bot_leases;
+DROP TABLE IF EXISTS gateway_instances;
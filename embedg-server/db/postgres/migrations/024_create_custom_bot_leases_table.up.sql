@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS gateway_instances (
+    id TEXT PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL,
+    heartbeat_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS custom_bot_leases (
+    custom_bot_id TEXT PRIMARY KEY REFERENCES custom_bots (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    instance_id TEXT NOT NULL,
+    acquired_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS custom_bot_leases_instance_id ON custom_bot_leases (instance_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS custom_bot_statuses (
+    custom_bot_id TEXT PRIMARY KEY REFERENCES custom_bots (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    instance_id TEXT,
+    gateway_connected BOOLEAN NOT NULL DEFAULT false,
+    gateway_ready_at TIMESTAMP,
+    gateway_disconnected_at TIMESTAMP,
+    gateway_disconnect_reason TEXT,
+    gateway_latency_ms INTEGER,
+    gateway_reconnects INTEGER NOT NULL DEFAULT 0,
+    last_interaction_at TIMESTAMP,
+    last_signature_failure_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE custom_commands DROP COLUMN name_localizations, DROP COLUMN description_localizations, DROP COLUMN default_member_permissions, DROP COLUMN contexts;
@@ -0,0 +1 @@
+ALTER TABLE custom_commands ADD COLUMN name_localizations JSONB NOT NULL DEFAULT '{}', ADD COLUMN description_localizations JSONB NOT NULL DEFAULT '{}', ADD COLUMN default_member_permissions BIGINT, ADD COLUMN contexts INTEGER[];
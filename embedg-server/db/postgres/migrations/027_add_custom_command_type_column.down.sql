@@ -0,0 +1 @@
+ALTER TABLE custom_commands DROP COLUMN type;
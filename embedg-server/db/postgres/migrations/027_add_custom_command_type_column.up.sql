@@ -0,0 +1 @@
+ALTER TABLE custom_commands ADD COLUMN type SMALLINT NOT NULL DEFAULT 1;
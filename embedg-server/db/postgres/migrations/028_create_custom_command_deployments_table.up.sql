@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS custom_command_deployments (
+    id TEXT PRIMARY KEY,
+    guild_id TEXT NOT NULL,
+    application_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    commands JSONB NOT NULL,
+    changes JSONB NOT NULL,
+    rollback_of TEXT REFERENCES custom_command_deployments (id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS custom_command_deployments_guild_id_created_at ON custom_command_deployments (guild_id, created_at);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS event_triggers (
+    id TEXT PRIMARY KEY,
+    creator_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    guild_id TEXT NOT NULL,
+    event TEXT NOT NULL,
+    name TEXT NOT NULL,
+    saved_message_id TEXT, -- The message that is sent when the event happens, if any
+    channel_id TEXT, -- The message is sent to the member as a DM if this is null
+    actions JSONB NOT NULL,
+    derived_permissions JSONB NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS event_triggers_guild_id_event ON event_triggers (guild_id, event);
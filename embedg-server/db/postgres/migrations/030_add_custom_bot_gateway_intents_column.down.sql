@@ -0,0 +1 @@
+ALTER TABLE custom_bot_statuses DROP COLUMN IF EXISTS gateway_intents;
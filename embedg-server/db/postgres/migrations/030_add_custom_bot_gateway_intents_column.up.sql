@@ -0,0 +1 @@
+ALTER TABLE custom_bot_statuses ADD COLUMN IF NOT EXISTS gateway_intents INTEGER;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS message_action_sets_guild_id;
+ALTER TABLE message_action_sets DROP COLUMN IF EXISTS guild_id;
@@ -0,0 +1,2 @@
+ALTER TABLE message_action_sets ADD COLUMN IF NOT EXISTS guild_id TEXT;
+CREATE INDEX IF NOT EXISTS message_action_sets_guild_id ON message_action_sets (guild_id);
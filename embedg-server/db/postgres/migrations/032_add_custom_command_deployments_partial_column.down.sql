@@ -0,0 +1 @@
+ALTER TABLE custom_command_deployments DROP COLUMN IF EXISTS partial;
@@ -0,0 +1 @@
+ALTER TABLE custom_command_deployments ADD COLUMN IF NOT EXISTS partial BOOLEAN NOT NULL DEFAULT false;
@@ -197,6 +197,28 @@ func (q *Queries) UpdateCustomBotPresence(ctx context.Context, arg UpdateCustomB
 	return i, err
 }
 
+const updateCustomBotToken = `-- name: UpdateCustomBotToken :execrows
+UPDATE custom_bots SET token = $1 WHERE id = $2 AND token = $3
+`
+
+type UpdateCustomBotTokenParams struct {
+	NewToken string
+	ID       string
+	OldToken string
+}
+
+func (q *Queries) UpdateCustomBotToken(ctx context.Context, arg UpdateCustomBotTokenParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateCustomBotToken,
+		arg.NewToken,
+		arg.ID,
+		arg.OldToken,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateCustomBotTokenInvalid = `-- name: UpdateCustomBotTokenInvalid :one
 UPDATE custom_bots SET token_invalid = $2 WHERE guild_id = $1 RETURNING id, guild_id, application_id, token, public_key, user_id, user_name, user_discriminator, user_avatar, handled_first_interaction, created_at, token_invalid, gateway_status, gateway_activity_type, gateway_activity_name, gateway_activity_state, gateway_activity_url
 `
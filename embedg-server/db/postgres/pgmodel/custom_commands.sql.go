@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/sqlc-dev/pqtype"
 )
 
@@ -26,7 +27,7 @@ func (q *Queries) CountCustomCommands(ctx context.Context, guildID string) (int6
 }
 
 const deleteCustomCommand = `-- name: DeleteCustomCommand :one
-DELETE FROM custom_commands WHERE id = $1 AND guild_id = $2 RETURNING id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at
+DELETE FROM custom_commands WHERE id = $1 AND guild_id = $2 RETURNING id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at, name_localizations, description_localizations, default_member_permissions, contexts, type
 `
 
 type DeleteCustomCommandParams struct {
@@ -50,12 +51,17 @@ func (q *Queries) DeleteCustomCommand(ctx context.Context, arg DeleteCustomComma
 		&i.DeployedAt,
 		&i.DerivedPermissions,
 		&i.LastUsedAt,
+		&i.NameLocalizations,
+		&i.DescriptionLocalizations,
+		&i.DefaultMemberPermissions,
+		pq.Array(&i.Contexts),
+		&i.Type,
 	)
 	return i, err
 }
 
 const getCustomCommand = `-- name: GetCustomCommand :one
-SELECT id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at FROM custom_commands WHERE id = $1 AND guild_id = $2
+SELECT id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at, name_localizations, description_localizations, default_member_permissions, contexts, type FROM custom_commands WHERE id = $1 AND guild_id = $2
 `
 
 type GetCustomCommandParams struct {
@@ -79,21 +85,31 @@ func (q *Queries) GetCustomCommand(ctx context.Context, arg GetCustomCommandPara
 		&i.DeployedAt,
 		&i.DerivedPermissions,
 		&i.LastUsedAt,
+		&i.NameLocalizations,
+		&i.DescriptionLocalizations,
+		&i.DefaultMemberPermissions,
+		pq.Array(&i.Contexts),
+		&i.Type,
 	)
 	return i, err
 }
 
 const getCustomCommandByName = `-- name: GetCustomCommandByName :one
-SELECT id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at FROM custom_commands WHERE name = $1 AND guild_id = $2
+SELECT id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at, name_localizations, description_localizations, default_member_permissions, contexts, type FROM custom_commands WHERE name = $1 AND guild_id = $2 AND type = $3
 `
 
 type GetCustomCommandByNameParams struct {
 	Name    string
 	GuildID string
+	Type    int16
 }
 
 func (q *Queries) GetCustomCommandByName(ctx context.Context, arg GetCustomCommandByNameParams) (CustomCommand, error) {
-	row := q.db.QueryRowContext(ctx, getCustomCommandByName, arg.Name, arg.GuildID)
+	row := q.db.QueryRowContext(ctx, getCustomCommandByName,
+		arg.Name,
+		arg.GuildID,
+		arg.Type,
+	)
 	var i CustomCommand
 	err := row.Scan(
 		&i.ID,
@@ -108,12 +124,17 @@ func (q *Queries) GetCustomCommandByName(ctx context.Context, arg GetCustomComma
 		&i.DeployedAt,
 		&i.DerivedPermissions,
 		&i.LastUsedAt,
+		&i.NameLocalizations,
+		&i.DescriptionLocalizations,
+		&i.DefaultMemberPermissions,
+		pq.Array(&i.Contexts),
+		&i.Type,
 	)
 	return i, err
 }
 
 const getCustomCommands = `-- name: GetCustomCommands :many
-SELECT id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at FROM custom_commands WHERE guild_id = $1
+SELECT id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at, name_localizations, description_localizations, default_member_permissions, contexts, type FROM custom_commands WHERE guild_id = $1
 `
 
 func (q *Queries) GetCustomCommands(ctx context.Context, guildID string) ([]CustomCommand, error) {
@@ -138,6 +159,61 @@ func (q *Queries) GetCustomCommands(ctx context.Context, guildID string) ([]Cust
 			&i.DeployedAt,
 			&i.DerivedPermissions,
 			&i.LastUsedAt,
+			&i.NameLocalizations,
+			&i.DescriptionLocalizations,
+			&i.DefaultMemberPermissions,
+			pq.Array(&i.Contexts),
+			&i.Type,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCustomCommandsPage = `-- name: GetCustomCommandsPage :many
+SELECT id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at, name_localizations, description_localizations, default_member_permissions, contexts, type FROM custom_commands WHERE id > $1 ORDER BY id LIMIT $2
+`
+
+type GetCustomCommandsPageParams struct {
+	ID    string
+	Limit int32
+}
+
+func (q *Queries) GetCustomCommandsPage(ctx context.Context, arg GetCustomCommandsPageParams) ([]CustomCommand, error) {
+	rows, err := q.db.QueryContext(ctx, getCustomCommandsPage, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CustomCommand
+	for rows.Next() {
+		var i CustomCommand
+		if err := rows.Scan(
+			&i.ID,
+			&i.GuildID,
+			&i.Name,
+			&i.Description,
+			&i.Enabled,
+			&i.Parameters,
+			&i.Actions,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeployedAt,
+			&i.DerivedPermissions,
+			&i.LastUsedAt,
+			&i.NameLocalizations,
+			&i.DescriptionLocalizations,
+			&i.DefaultMemberPermissions,
+			pq.Array(&i.Contexts),
+			&i.Type,
 		); err != nil {
 			return nil, err
 		}
@@ -153,19 +229,25 @@ func (q *Queries) GetCustomCommands(ctx context.Context, guildID string) ([]Cust
 }
 
 const insertCustomCommand = `-- name: InsertCustomCommand :one
-INSERT INTO custom_commands (id, guild_id, name, description, parameters, actions, derived_permissions, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at
+INSERT INTO custom_commands (id, guild_id, name, description, parameters, actions, derived_permissions, created_at, updated_at, name_localizations, description_localizations, default_member_permissions, contexts, type, enabled) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at, name_localizations, description_localizations, default_member_permissions, contexts, type
 `
 
 type InsertCustomCommandParams struct {
-	ID                 string
-	GuildID            string
-	Name               string
-	Description        string
-	Parameters         json.RawMessage
-	Actions            json.RawMessage
-	DerivedPermissions pqtype.NullRawMessage
-	CreatedAt          time.Time
-	UpdatedAt          time.Time
+	ID                       string
+	GuildID                  string
+	Name                     string
+	Description              string
+	Parameters               json.RawMessage
+	Actions                  json.RawMessage
+	DerivedPermissions       pqtype.NullRawMessage
+	CreatedAt                time.Time
+	UpdatedAt                time.Time
+	NameLocalizations        json.RawMessage
+	DescriptionLocalizations json.RawMessage
+	DefaultMemberPermissions sql.NullInt64
+	Contexts                 []int32
+	Type                     int16
+	Enabled                  bool
 }
 
 func (q *Queries) InsertCustomCommand(ctx context.Context, arg InsertCustomCommandParams) (CustomCommand, error) {
@@ -179,6 +261,12 @@ func (q *Queries) InsertCustomCommand(ctx context.Context, arg InsertCustomComma
 		arg.DerivedPermissions,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.NameLocalizations,
+		arg.DescriptionLocalizations,
+		arg.DefaultMemberPermissions,
+		pq.Array(arg.Contexts),
+		arg.Type,
+		arg.Enabled,
 	)
 	var i CustomCommand
 	err := row.Scan(
@@ -194,12 +282,17 @@ func (q *Queries) InsertCustomCommand(ctx context.Context, arg InsertCustomComma
 		&i.DeployedAt,
 		&i.DerivedPermissions,
 		&i.LastUsedAt,
+		&i.NameLocalizations,
+		&i.DescriptionLocalizations,
+		&i.DefaultMemberPermissions,
+		pq.Array(&i.Contexts),
+		&i.Type,
 	)
 	return i, err
 }
 
 const setCustomCommandsDeployedAt = `-- name: SetCustomCommandsDeployedAt :one
-UPDATE custom_commands SET deployed_at = $2 WHERE guild_id = $1 RETURNING id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at
+UPDATE custom_commands SET deployed_at = $2 WHERE guild_id = $1 RETURNING id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at, name_localizations, description_localizations, default_member_permissions, contexts, type
 `
 
 type SetCustomCommandsDeployedAtParams struct {
@@ -223,24 +316,34 @@ func (q *Queries) SetCustomCommandsDeployedAt(ctx context.Context, arg SetCustom
 		&i.DeployedAt,
 		&i.DerivedPermissions,
 		&i.LastUsedAt,
+		&i.NameLocalizations,
+		&i.DescriptionLocalizations,
+		&i.DefaultMemberPermissions,
+		pq.Array(&i.Contexts),
+		&i.Type,
 	)
 	return i, err
 }
 
 const updateCustomCommand = `-- name: UpdateCustomCommand :one
-UPDATE custom_commands SET name = $3, description = $4, enabled = $5, actions = $6, parameters = $7, derived_permissions = $8, updated_at = $9 WHERE id = $1 AND guild_id = $2 RETURNING id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at
+UPDATE custom_commands SET name = $3, description = $4, enabled = $5, actions = $6, parameters = $7, derived_permissions = $8, updated_at = $9, name_localizations = $10, description_localizations = $11, default_member_permissions = $12, contexts = $13, type = $14 WHERE id = $1 AND guild_id = $2 RETURNING id, guild_id, name, description, enabled, parameters, actions, created_at, updated_at, deployed_at, derived_permissions, last_used_at, name_localizations, description_localizations, default_member_permissions, contexts, type
 `
 
 type UpdateCustomCommandParams struct {
-	ID                 string
-	GuildID            string
-	Name               string
-	Description        string
-	Enabled            bool
-	Actions            json.RawMessage
-	Parameters         json.RawMessage
-	DerivedPermissions pqtype.NullRawMessage
-	UpdatedAt          time.Time
+	ID                       string
+	GuildID                  string
+	Name                     string
+	Description              string
+	Enabled                  bool
+	Actions                  json.RawMessage
+	Parameters               json.RawMessage
+	DerivedPermissions       pqtype.NullRawMessage
+	UpdatedAt                time.Time
+	NameLocalizations        json.RawMessage
+	DescriptionLocalizations json.RawMessage
+	DefaultMemberPermissions sql.NullInt64
+	Contexts                 []int32
+	Type                     int16
 }
 
 func (q *Queries) UpdateCustomCommand(ctx context.Context, arg UpdateCustomCommandParams) (CustomCommand, error) {
@@ -254,6 +357,11 @@ func (q *Queries) UpdateCustomCommand(ctx context.Context, arg UpdateCustomComma
 		arg.Parameters,
 		arg.DerivedPermissions,
 		arg.UpdatedAt,
+		arg.NameLocalizations,
+		arg.DescriptionLocalizations,
+		arg.DefaultMemberPermissions,
+		pq.Array(arg.Contexts),
+		arg.Type,
 	)
 	var i CustomCommand
 	err := row.Scan(
@@ -269,6 +377,25 @@ func (q *Queries) UpdateCustomCommand(ctx context.Context, arg UpdateCustomComma
 		&i.DeployedAt,
 		&i.DerivedPermissions,
 		&i.LastUsedAt,
+		&i.NameLocalizations,
+		&i.DescriptionLocalizations,
+		&i.DefaultMemberPermissions,
+		pq.Array(&i.Contexts),
+		&i.Type,
 	)
 	return i, err
 }
+
+const updateCustomCommandActions = `-- name: UpdateCustomCommandActions :exec
+UPDATE custom_commands SET actions = $2 WHERE id = $1
+`
+
+type UpdateCustomCommandActionsParams struct {
+	ID      string
+	Actions json.RawMessage
+}
+
+func (q *Queries) UpdateCustomCommandActions(ctx context.Context, arg UpdateCustomCommandActionsParams) error {
+	_, err := q.db.ExecContext(ctx, updateCustomCommandActions, arg.ID, arg.Actions)
+	return err
+}
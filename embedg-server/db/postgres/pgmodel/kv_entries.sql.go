@@ -8,11 +8,94 @@ package pgmodel
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
+
+	"github.com/lib/pq"
 )
 
+const addKVEntrySetItem = `-- name: AddKVEntrySetItem :one
+INSERT INTO kv_entries (
+    key, 
+    guild_id, 
+    value, 
+    expires_at, 
+    created_at, 
+    updated_at
+) VALUES (
+    $1, 
+    $2, 
+    $3, 
+    NULL, 
+    $4, 
+    $5
+) ON CONFLICT (key, guild_id)
+DO UPDATE SET 
+    value = (CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN '[]'::jsonb 
+        ELSE kv_entries.value::jsonb 
+    END || EXCLUDED.value::jsonb)::text, 
+    expires_at = CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN NULL 
+        ELSE kv_entries.expires_at 
+    END, 
+    updated_at = EXCLUDED.updated_at
+WHERE CASE 
+    WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN true 
+    WHEN jsonb_typeof(kv_entries.value::jsonb) <> 'array' THEN false 
+    ELSE NOT EXISTS (
+        SELECT 1 FROM jsonb_array_elements(kv_entries.value::jsonb) AS e(item) WHERE e.item = EXCLUDED.value::jsonb -> 0
+    ) 
+END
+RETURNING key, guild_id, value, expires_at, created_at, updated_at
+`
+
+type AddKVEntrySetItemParams struct {
+	Key       string
+	GuildID   string
+	Value     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) AddKVEntrySetItem(ctx context.Context, arg AddKVEntrySetItemParams) (KvEntry, error) {
+	row := q.db.QueryRowContext(ctx, addKVEntrySetItem,
+		arg.Key,
+		arg.GuildID,
+		arg.Value,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i KvEntry
+	err := row.Scan(
+		&i.Key,
+		&i.GuildID,
+		&i.Value,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const countExistingKVEntries = `-- name: CountExistingKVEntries :one
+SELECT COUNT(*) FROM kv_entries WHERE guild_id = $1 AND key = ANY($2::TEXT[]) AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC')
+`
+
+type CountExistingKVEntriesParams struct {
+	GuildID string
+	Keys    []string
+}
+
+func (q *Queries) CountExistingKVEntries(ctx context.Context, arg CountExistingKVEntriesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countExistingKVEntries, arg.GuildID, pq.Array(arg.Keys))
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countKVEntries = `-- name: CountKVEntries :one
-SELECT COUNT(*) FROM kv_entries WHERE guild_id = $1
+SELECT COUNT(*) FROM kv_entries WHERE guild_id = $1 AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC')
 `
 
 func (q *Queries) CountKVEntries(ctx context.Context, guildID string) (int64, error) {
@@ -22,6 +105,41 @@ func (q *Queries) CountKVEntries(ctx context.Context, guildID string) (int64, er
 	return count, err
 }
 
+const countMatchingKVEntries = `-- name: CountMatchingKVEntries :one
+SELECT COUNT(*) FROM kv_entries WHERE key LIKE $1 AND guild_id = $2 AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC')
+`
+
+type CountMatchingKVEntriesParams struct {
+	Key     string
+	GuildID string
+}
+
+func (q *Queries) CountMatchingKVEntries(ctx context.Context, arg CountMatchingKVEntriesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countMatchingKVEntries, arg.Key, arg.GuildID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteExpiredKVEntries = `-- name: DeleteExpiredKVEntries :execrows
+DELETE FROM kv_entries WHERE (key, guild_id) IN (
+    SELECT key, guild_id FROM kv_entries WHERE expires_at <= $1 LIMIT $2
+)
+`
+
+type DeleteExpiredKVEntriesParams struct {
+	ExpiresAt sql.NullTime
+	Limit     int32
+}
+
+func (q *Queries) DeleteExpiredKVEntries(ctx context.Context, arg DeleteExpiredKVEntriesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredKVEntries, arg.ExpiresAt, arg.Limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteKVEntry = `-- name: DeleteKVEntry :one
 DELETE FROM kv_entries WHERE key = $1 AND guild_id = $2 RETURNING key, guild_id, value, expires_at, created_at, updated_at
 `
@@ -46,7 +164,7 @@ func (q *Queries) DeleteKVEntry(ctx context.Context, arg DeleteKVEntryParams) (K
 }
 
 const getKVEntry = `-- name: GetKVEntry :one
-SELECT key, guild_id, value, expires_at, created_at, updated_at FROM kv_entries WHERE key = $1 AND guild_id = $2
+SELECT key, guild_id, value, expires_at, created_at, updated_at FROM kv_entries WHERE key = $1 AND guild_id = $2 AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC')
 `
 
 type GetKVEntryParams struct {
@@ -85,8 +203,14 @@ INSERT INTO kv_entries (
     $6
 ) ON CONFLICT (key, guild_id)
 DO UPDATE SET 
-    value = kv_entries.value::int + EXCLUDED.value::int, 
-    expires_at = EXCLUDED.expires_at, 
+    value = CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN EXCLUDED.value 
+        ELSE (kv_entries.value::int + EXCLUDED.value::int)::text 
+    END, 
+    expires_at = CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN EXCLUDED.expires_at 
+        ELSE COALESCE(EXCLUDED.expires_at, kv_entries.expires_at) 
+    END, 
     updated_at = EXCLUDED.updated_at
 RETURNING key, guild_id, value, expires_at, created_at, updated_at
 `
@@ -121,8 +245,180 @@ func (q *Queries) IncreaseKVEntry(ctx context.Context, arg IncreaseKVEntryParams
 	return i, err
 }
 
+const kVEntryListContains = `-- name: KVEntryListContains :one
+SELECT EXISTS (
+    SELECT 1 FROM kv_entries, jsonb_array_elements(CASE 
+        WHEN jsonb_typeof(kv_entries.value::jsonb) = 'array' THEN kv_entries.value::jsonb 
+        ELSE '[]'::jsonb 
+    END) AS e(item)
+    WHERE kv_entries.key = $1 AND kv_entries.guild_id = $2 
+        AND (kv_entries.expires_at IS NULL OR kv_entries.expires_at > NOW() AT TIME ZONE 'UTC') 
+        AND e.item = $3::jsonb
+)
+`
+
+type KVEntryListContainsParams struct {
+	Key     string
+	GuildID string
+	Item    json.RawMessage
+}
+
+func (q *Queries) KVEntryListContains(ctx context.Context, arg KVEntryListContainsParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, kVEntryListContains, arg.Key, arg.GuildID, arg.Item)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listKVEntries = `-- name: ListKVEntries :many
+SELECT key, guild_id, value, expires_at, created_at, updated_at FROM kv_entries WHERE key LIKE $1 AND guild_id = $2 AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC') ORDER BY key LIMIT $3 OFFSET $4
+`
+
+type ListKVEntriesParams struct {
+	Key         string
+	GuildID     string
+	MaxEntries  int32
+	SkipEntries int32
+}
+
+func (q *Queries) ListKVEntries(ctx context.Context, arg ListKVEntriesParams) ([]KvEntry, error) {
+	rows, err := q.db.QueryContext(ctx, listKVEntries,
+		arg.Key,
+		arg.GuildID,
+		arg.MaxEntries,
+		arg.SkipEntries,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []KvEntry
+	for rows.Next() {
+		var i KvEntry
+		if err := rows.Scan(
+			&i.Key,
+			&i.GuildID,
+			&i.Value,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const pushKVEntryList = `-- name: PushKVEntryList :one
+INSERT INTO kv_entries (
+    key, 
+    guild_id, 
+    value, 
+    expires_at, 
+    created_at, 
+    updated_at
+) VALUES (
+    $1, 
+    $2, 
+    $3, 
+    NULL, 
+    $4, 
+    $5
+) ON CONFLICT (key, guild_id)
+DO UPDATE SET 
+    value = (CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN '[]'::jsonb 
+        ELSE kv_entries.value::jsonb 
+    END || EXCLUDED.value::jsonb)::text, 
+    expires_at = CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN NULL 
+        ELSE kv_entries.expires_at 
+    END, 
+    updated_at = EXCLUDED.updated_at
+WHERE CASE 
+    WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN true 
+    ELSE jsonb_typeof(kv_entries.value::jsonb) = 'array' 
+END
+RETURNING key, guild_id, value, expires_at, created_at, updated_at
+`
+
+type PushKVEntryListParams struct {
+	Key       string
+	GuildID   string
+	Value     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) PushKVEntryList(ctx context.Context, arg PushKVEntryListParams) (KvEntry, error) {
+	row := q.db.QueryRowContext(ctx, pushKVEntryList,
+		arg.Key,
+		arg.GuildID,
+		arg.Value,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i KvEntry
+	err := row.Scan(
+		&i.Key,
+		&i.GuildID,
+		&i.Value,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const removeKVEntryListItem = `-- name: RemoveKVEntryListItem :one
+UPDATE kv_entries SET 
+    value = (
+        SELECT COALESCE(jsonb_agg(e.item ORDER BY e.idx), '[]'::jsonb) 
+        FROM jsonb_array_elements(kv_entries.value::jsonb) WITH ORDINALITY AS e(item, idx) 
+        WHERE e.item <> $1::jsonb
+    )::text, 
+    updated_at = $2
+WHERE key = $3 AND guild_id = $4 
+    AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC') 
+    AND jsonb_typeof(value::jsonb) = 'array'
+RETURNING key, guild_id, value, expires_at, created_at, updated_at
+`
+
+type RemoveKVEntryListItemParams struct {
+	Item      json.RawMessage
+	UpdatedAt time.Time
+	Key       string
+	GuildID   string
+}
+
+func (q *Queries) RemoveKVEntryListItem(ctx context.Context, arg RemoveKVEntryListItemParams) (KvEntry, error) {
+	row := q.db.QueryRowContext(ctx, removeKVEntryListItem,
+		arg.Item,
+		arg.UpdatedAt,
+		arg.Key,
+		arg.GuildID,
+	)
+	var i KvEntry
+	err := row.Scan(
+		&i.Key,
+		&i.GuildID,
+		&i.Value,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const searchKVEntries = `-- name: SearchKVEntries :many
-SELECT key, guild_id, value, expires_at, created_at, updated_at FROM kv_entries WHERE key LIKE $1 AND guild_id = $2
+SELECT key, guild_id, value, expires_at, created_at, updated_at FROM kv_entries WHERE key LIKE $1 AND guild_id = $2 AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC')
 `
 
 type SearchKVEntriesParams struct {
@@ -160,6 +456,39 @@ func (q *Queries) SearchKVEntries(ctx context.Context, arg SearchKVEntriesParams
 	return items, nil
 }
 
+const searchKVEntryKeys = `-- name: SearchKVEntryKeys :many
+SELECT key FROM kv_entries WHERE key LIKE $1 AND guild_id = $2 AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC') ORDER BY key LIMIT $3
+`
+
+type SearchKVEntryKeysParams struct {
+	Key     string
+	GuildID string
+	MaxKeys int32
+}
+
+func (q *Queries) SearchKVEntryKeys(ctx context.Context, arg SearchKVEntryKeysParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, searchKVEntryKeys, arg.Key, arg.GuildID, arg.MaxKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		items = append(items, key)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const setKVEntry = `-- name: SetKVEntry :exec
 INSERT INTO kv_entries (
     key, 
@@ -202,3 +531,94 @@ func (q *Queries) SetKVEntry(ctx context.Context, arg SetKVEntryParams) error {
 	)
 	return err
 }
+
+const setKVEntryExpiresAt = `-- name: SetKVEntryExpiresAt :one
+UPDATE kv_entries SET expires_at = $3, updated_at = $4 WHERE key = $1 AND guild_id = $2 AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC') RETURNING key, guild_id, value, expires_at, created_at, updated_at
+`
+
+type SetKVEntryExpiresAtParams struct {
+	Key       string
+	GuildID   string
+	ExpiresAt sql.NullTime
+	UpdatedAt time.Time
+}
+
+func (q *Queries) SetKVEntryExpiresAt(ctx context.Context, arg SetKVEntryExpiresAtParams) (KvEntry, error) {
+	row := q.db.QueryRowContext(ctx, setKVEntryExpiresAt,
+		arg.Key,
+		arg.GuildID,
+		arg.ExpiresAt,
+		arg.UpdatedAt,
+	)
+	var i KvEntry
+	err := row.Scan(
+		&i.Key,
+		&i.GuildID,
+		&i.Value,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setKVEntryMapField = `-- name: SetKVEntryMapField :one
+INSERT INTO kv_entries (
+    key, 
+    guild_id, 
+    value, 
+    expires_at, 
+    created_at, 
+    updated_at
+) VALUES (
+    $1, 
+    $2, 
+    $3, 
+    NULL, 
+    $4, 
+    $5
+) ON CONFLICT (key, guild_id)
+DO UPDATE SET 
+    value = (CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN '{}'::jsonb 
+        ELSE kv_entries.value::jsonb 
+    END || EXCLUDED.value::jsonb)::text, 
+    expires_at = CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN NULL 
+        ELSE kv_entries.expires_at 
+    END, 
+    updated_at = EXCLUDED.updated_at
+WHERE CASE 
+    WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN true 
+    ELSE jsonb_typeof(kv_entries.value::jsonb) = 'object' 
+END
+RETURNING key, guild_id, value, expires_at, created_at, updated_at
+`
+
+type SetKVEntryMapFieldParams struct {
+	Key       string
+	GuildID   string
+	Value     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *Queries) SetKVEntryMapField(ctx context.Context, arg SetKVEntryMapFieldParams) (KvEntry, error) {
+	row := q.db.QueryRowContext(ctx, setKVEntryMapField,
+		arg.Key,
+		arg.GuildID,
+		arg.Value,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i KvEntry
+	err := row.Scan(
+		&i.Key,
+		&i.GuildID,
+		&i.Value,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
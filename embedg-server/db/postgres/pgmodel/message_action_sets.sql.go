@@ -7,6 +7,7 @@ package pgmodel
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 
 	"github.com/sqlc-dev/pqtype"
@@ -22,7 +23,7 @@ func (q *Queries) DeleteMessageActionSetsForMessage(ctx context.Context, message
 }
 
 const getMessageActionSet = `-- name: GetMessageActionSet :one
-SELECT id, message_id, set_id, actions, derived_permissions, last_used_at, ephemeral FROM message_action_sets WHERE message_id = $1 AND set_id = $2
+SELECT id, message_id, set_id, actions, derived_permissions, last_used_at, ephemeral, guild_id FROM message_action_sets WHERE message_id = $1 AND set_id = $2
 `
 
 type GetMessageActionSetParams struct {
@@ -41,12 +42,13 @@ func (q *Queries) GetMessageActionSet(ctx context.Context, arg GetMessageActionS
 		&i.DerivedPermissions,
 		&i.LastUsedAt,
 		&i.Ephemeral,
+		&i.GuildID,
 	)
 	return i, err
 }
 
 const getMessageActionSets = `-- name: GetMessageActionSets :many
-SELECT id, message_id, set_id, actions, derived_permissions, last_used_at, ephemeral FROM message_action_sets WHERE message_id = $1
+SELECT id, message_id, set_id, actions, derived_permissions, last_used_at, ephemeral, guild_id FROM message_action_sets WHERE message_id = $1
 `
 
 func (q *Queries) GetMessageActionSets(ctx context.Context, messageID string) ([]MessageActionSet, error) {
@@ -66,6 +68,90 @@ func (q *Queries) GetMessageActionSets(ctx context.Context, messageID string) ([
 			&i.DerivedPermissions,
 			&i.LastUsedAt,
 			&i.Ephemeral,
+			&i.GuildID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMessageActionSetsPage = `-- name: GetMessageActionSetsPage :many
+SELECT id, message_id, set_id, actions, derived_permissions, last_used_at, ephemeral, guild_id FROM message_action_sets WHERE id > $1 ORDER BY id LIMIT $2
+`
+
+type GetMessageActionSetsPageParams struct {
+	ID    string
+	Limit int32
+}
+
+func (q *Queries) GetMessageActionSetsPage(ctx context.Context, arg GetMessageActionSetsPageParams) ([]MessageActionSet, error) {
+	rows, err := q.db.QueryContext(ctx, getMessageActionSetsPage, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MessageActionSet
+	for rows.Next() {
+		var i MessageActionSet
+		if err := rows.Scan(
+			&i.ID,
+			&i.MessageID,
+			&i.SetID,
+			&i.Actions,
+			&i.DerivedPermissions,
+			&i.LastUsedAt,
+			&i.Ephemeral,
+			&i.GuildID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMessageActionSetsForGuildPage = `-- name: GetMessageActionSetsForGuildPage :many
+SELECT id, message_id, set_id, actions, derived_permissions, last_used_at, ephemeral, guild_id FROM message_action_sets WHERE guild_id = $1 AND id > $2 ORDER BY id LIMIT $3
+`
+
+type GetMessageActionSetsForGuildPageParams struct {
+	GuildID sql.NullString
+	ID      string
+	Limit   int32
+}
+
+func (q *Queries) GetMessageActionSetsForGuildPage(ctx context.Context, arg GetMessageActionSetsForGuildPageParams) ([]MessageActionSet, error) {
+	rows, err := q.db.QueryContext(ctx, getMessageActionSetsForGuildPage, arg.GuildID, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MessageActionSet
+	for rows.Next() {
+		var i MessageActionSet
+		if err := rows.Scan(
+			&i.ID,
+			&i.MessageID,
+			&i.SetID,
+			&i.Actions,
+			&i.DerivedPermissions,
+			&i.LastUsedAt,
+			&i.Ephemeral,
+			&i.GuildID,
 		); err != nil {
 			return nil, err
 		}
@@ -81,7 +167,7 @@ func (q *Queries) GetMessageActionSets(ctx context.Context, messageID string) ([
 }
 
 const insertMessageActionSet = `-- name: InsertMessageActionSet :one
-INSERT INTO message_action_sets (id, message_id, set_id, actions, derived_permissions, ephemeral) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, message_id, set_id, actions, derived_permissions, last_used_at, ephemeral
+INSERT INTO message_action_sets (id, message_id, set_id, actions, derived_permissions, ephemeral, guild_id) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, message_id, set_id, actions, derived_permissions, last_used_at, ephemeral, guild_id
 `
 
 type InsertMessageActionSetParams struct {
@@ -91,6 +177,7 @@ type InsertMessageActionSetParams struct {
 	Actions            json.RawMessage
 	DerivedPermissions pqtype.NullRawMessage
 	Ephemeral          bool
+	GuildID            sql.NullString
 }
 
 func (q *Queries) InsertMessageActionSet(ctx context.Context, arg InsertMessageActionSetParams) (MessageActionSet, error) {
@@ -101,6 +188,7 @@ func (q *Queries) InsertMessageActionSet(ctx context.Context, arg InsertMessageA
 		arg.Actions,
 		arg.DerivedPermissions,
 		arg.Ephemeral,
+		arg.GuildID,
 	)
 	var i MessageActionSet
 	err := row.Scan(
@@ -111,6 +199,21 @@ func (q *Queries) InsertMessageActionSet(ctx context.Context, arg InsertMessageA
 		&i.DerivedPermissions,
 		&i.LastUsedAt,
 		&i.Ephemeral,
+		&i.GuildID,
 	)
 	return i, err
 }
+
+const updateMessageActionSetActions = `-- name: UpdateMessageActionSetActions :exec
+UPDATE message_action_sets SET actions = $2 WHERE id = $1
+`
+
+type UpdateMessageActionSetActionsParams struct {
+	ID      string
+	Actions json.RawMessage
+}
+
+func (q *Queries) UpdateMessageActionSetActions(ctx context.Context, arg UpdateMessageActionSetActionsParams) error {
+	_, err := q.db.ExecContext(ctx, updateMessageActionSetActions, arg.ID, arg.Actions)
+	return err
+}
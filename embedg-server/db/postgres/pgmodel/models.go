@@ -32,19 +32,58 @@ type CustomBot struct {
 	GatewayActivityUrl      sql.NullString
 }
 
+type CustomBotLease struct {
+	CustomBotID string
+	InstanceID  string
+	AcquiredAt  time.Time
+	ExpiresAt   time.Time
+}
+
+type CustomBotStatus struct {
+	CustomBotID             string
+	InstanceID              sql.NullString
+	GatewayConnected        bool
+	GatewayReadyAt          sql.NullTime
+	GatewayDisconnectedAt   sql.NullTime
+	GatewayDisconnectReason sql.NullString
+	GatewayLatencyMs        sql.NullInt32
+	GatewayReconnects       int32
+	LastInteractionAt       sql.NullTime
+	LastSignatureFailureAt  sql.NullTime
+	UpdatedAt               time.Time
+	GatewayIntents          sql.NullInt32
+}
+
 type CustomCommand struct {
-	ID                 string
-	GuildID            string
-	Name               string
-	Description        string
-	Enabled            bool
-	Parameters         json.RawMessage
-	Actions            json.RawMessage
-	CreatedAt          time.Time
-	UpdatedAt          time.Time
-	DeployedAt         sql.NullTime
-	DerivedPermissions pqtype.NullRawMessage
-	LastUsedAt         time.Time
+	ID                       string
+	GuildID                  string
+	Name                     string
+	Description              string
+	Enabled                  bool
+	Parameters               json.RawMessage
+	Actions                  json.RawMessage
+	CreatedAt                time.Time
+	UpdatedAt                time.Time
+	DeployedAt               sql.NullTime
+	DerivedPermissions       pqtype.NullRawMessage
+	LastUsedAt               time.Time
+	NameLocalizations        json.RawMessage
+	DescriptionLocalizations json.RawMessage
+	DefaultMemberPermissions sql.NullInt64
+	Contexts                 []int32
+	Type                     int16
+}
+
+type CustomCommandDeployment struct {
+	ID            string
+	GuildID       string
+	ApplicationID string
+	UserID        string
+	Commands      json.RawMessage
+	Changes       json.RawMessage
+	RollbackOf    sql.NullString
+	CreatedAt     time.Time
+	Partial       bool
 }
 
 type EmbedLink struct {
@@ -78,6 +117,35 @@ type Entitlement struct {
 	ConsumedGuildID sql.NullString
 }
 
+type EventTrigger struct {
+	ID                 string
+	CreatorID          string
+	GuildID            string
+	Event              string
+	Name               string
+	SavedMessageID     sql.NullString
+	ChannelID          sql.NullString
+	Actions            json.RawMessage
+	DerivedPermissions json.RawMessage
+	Enabled            bool
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+type GatewayInstance struct {
+	ID          string
+	StartedAt   time.Time
+	HeartbeatAt time.Time
+}
+
+type GuildTranslation struct {
+	GuildID   string
+	Locale    string
+	Key       string
+	Value     string
+	UpdatedAt time.Time
+}
+
 type Image struct {
 	ID              string
 	UserID          string
@@ -106,6 +174,7 @@ type MessageActionSet struct {
 	DerivedPermissions pqtype.NullRawMessage
 	LastUsedAt         time.Time
 	Ephemeral          bool
+	GuildID            sql.NullString
 }
 
 type SavedMessage struct {
@@ -118,6 +187,13 @@ type SavedMessage struct {
 	Data        json.RawMessage
 }
 
+type SavedMessageVariant struct {
+	SavedMessageID string
+	Locale         string
+	Data           json.RawMessage
+	UpdatedAt      time.Time
+}
+
 type ScheduledMessage struct {
 	ID             string
 	CreatorID      string
@@ -155,6 +231,20 @@ type SharedMessage struct {
 	Data      json.RawMessage
 }
 
+type TemplateHttpHost struct {
+	GuildID   string
+	Host      string
+	CreatedAt time.Time
+}
+
+type TemplateSnippet struct {
+	GuildID   string
+	Name      string
+	Content   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
 type User struct {
 	ID            string
 	Name          string
@@ -134,6 +134,46 @@ func (q *Queries) GetSavedMessagesForGuild(ctx context.Context, guildID sql.Null
 	return items, nil
 }
 
+const getSavedMessagesPage = `-- name: GetSavedMessagesPage :many
+SELECT id, creator_id, guild_id, updated_at, name, description, data FROM saved_messages WHERE id > $1 ORDER BY id LIMIT $2
+`
+
+type GetSavedMessagesPageParams struct {
+	ID    string
+	Limit int32
+}
+
+func (q *Queries) GetSavedMessagesPage(ctx context.Context, arg GetSavedMessagesPageParams) ([]SavedMessage, error) {
+	rows, err := q.db.QueryContext(ctx, getSavedMessagesPage, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SavedMessage
+	for rows.Next() {
+		var i SavedMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatorID,
+			&i.GuildID,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Description,
+			&i.Data,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertSavedMessage = `-- name: InsertSavedMessage :one
 INSERT INTO saved_messages (id, creator_id, guild_id, updated_at, name, description, data) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, creator_id, guild_id, updated_at, name, description, data
 `
@@ -171,6 +211,20 @@ func (q *Queries) InsertSavedMessage(ctx context.Context, arg InsertSavedMessage
 	return i, err
 }
 
+const updateSavedMessageData = `-- name: UpdateSavedMessageData :exec
+UPDATE saved_messages SET data = $2 WHERE id = $1
+`
+
+type UpdateSavedMessageDataParams struct {
+	ID   string
+	Data json.RawMessage
+}
+
+func (q *Queries) UpdateSavedMessageData(ctx context.Context, arg UpdateSavedMessageDataParams) error {
+	_, err := q.db.ExecContext(ctx, updateSavedMessageData, arg.ID, arg.Data)
+	return err
+}
+
 const updateSavedMessageForCreator = `-- name: UpdateSavedMessageForCreator :one
 UPDATE saved_messages SET updated_at = $3, name = $4, description = $5, data = $6 WHERE id = $1 AND creator_id = $2 RETURNING id, creator_id, guild_id, updated_at, name, description, data
 `
@@ -73,6 +73,45 @@ func (q *Queries) GetSessionsForUser(ctx context.Context, userID string) ([]Sess
 	return items, nil
 }
 
+const getSessionsPage = `-- name: GetSessionsPage :many
+SELECT token_hash, user_id, guild_ids, access_token, created_at, expires_at FROM sessions WHERE token_hash > $1 ORDER BY token_hash LIMIT $2
+`
+
+type GetSessionsPageParams struct {
+	TokenHash string
+	Limit     int32
+}
+
+func (q *Queries) GetSessionsPage(ctx context.Context, arg GetSessionsPageParams) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionsPage, arg.TokenHash, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Session
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.TokenHash,
+			&i.UserID,
+			pq.Array(&i.GuildIds),
+			&i.AccessToken,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertSession = `-- name: InsertSession :one
 INSERT INTO sessions (token_hash, user_id, guild_ids, access_token, created_at, expires_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING token_hash, user_id, guild_ids, access_token, created_at, expires_at
 `
@@ -106,3 +145,25 @@ func (q *Queries) InsertSession(ctx context.Context, arg InsertSessionParams) (S
 	)
 	return i, err
 }
+
+const updateSessionAccessToken = `-- name: UpdateSessionAccessToken :execrows
+UPDATE sessions SET access_token = $1 WHERE token_hash = $2 AND access_token = $3
+`
+
+type UpdateSessionAccessTokenParams struct {
+	NewAccessToken string
+	TokenHash      string
+	OldAccessToken string
+}
+
+func (q *Queries) UpdateSessionAccessToken(ctx context.Context, arg UpdateSessionAccessTokenParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateSessionAccessToken,
+		arg.NewAccessToken,
+		arg.TokenHash,
+		arg.OldAccessToken,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,28 @@
+-- name: UpsertGatewayInstance :exec
+INSERT INTO gateway_instances (id, started_at, heartbeat_at) VALUES ($1, $2, $3) 
+ON CONFLICT (id) DO UPDATE SET heartbeat_at = EXCLUDED.heartbeat_at;
+
+-- name: DeleteGatewayInstance :exec
+DELETE FROM gateway_instances WHERE id = $1;
+
+-- name: DeleteStaleGatewayInstances :execrows
+DELETE FROM gateway_instances WHERE heartbeat_at < $1;
+
+-- name: CountGatewayInstances :one
+SELECT COUNT(*) FROM gateway_instances;
+
+-- name: AcquireCustomBotLease :execrows
+INSERT INTO custom_bot_leases (custom_bot_id, instance_id, acquired_at, expires_at) 
+VALUES (@custom_bot_id, @instance_id, NOW() AT TIME ZONE 'UTC', NOW() AT TIME ZONE 'UTC' + make_interval(secs => @ttl_seconds::INTEGER)) 
+ON CONFLICT (custom_bot_id) DO UPDATE SET instance_id = EXCLUDED.instance_id, acquired_at = EXCLUDED.acquired_at, expires_at = EXCLUDED.expires_at 
+WHERE custom_bot_leases.expires_at < EXCLUDED.acquired_at OR custom_bot_leases.instance_id = EXCLUDED.instance_id;
+
+-- name: RenewCustomBotLeases :many
+UPDATE custom_bot_leases SET expires_at = NOW() AT TIME ZONE 'UTC' + make_interval(secs => @ttl_seconds::INTEGER) 
+WHERE instance_id = @instance_id AND custom_bot_id = ANY(@custom_bot_ids::TEXT[]) RETURNING custom_bot_id;
+
+-- name: ReleaseCustomBotLease :exec
+DELETE FROM custom_bot_leases WHERE custom_bot_id = $1 AND instance_id = $2;
+
+-- name: GetCustomBotLease :one
+SELECT * FROM custom_bot_leases WHERE custom_bot_id = $1;
@@ -0,0 +1,23 @@
+-- name: GetCustomBotStatus :one
+SELECT * FROM custom_bot_statuses WHERE custom_bot_id = $1;
+
+-- name: SetCustomBotGatewayConnected :exec
+INSERT INTO custom_bot_statuses (custom_bot_id, instance_id, gateway_connected, gateway_ready_at, gateway_intents, updated_at) VALUES ($1, $2, true, $3, $4, $3) 
+ON CONFLICT (custom_bot_id) DO UPDATE SET instance_id = EXCLUDED.instance_id, gateway_connected = true, gateway_ready_at = EXCLUDED.gateway_ready_at, gateway_intents = EXCLUDED.gateway_intents, updated_at = EXCLUDED.updated_at;
+
+-- name: SetCustomBotGatewayDisconnected :exec
+UPDATE custom_bot_statuses SET gateway_connected = false, gateway_disconnected_at = $3, gateway_disconnect_reason = $4, gateway_latency_ms = NULL, updated_at = $3 WHERE custom_bot_id = $1 AND instance_id = $2;
+
+-- name: IncrementCustomBotGatewayReconnects :exec
+UPDATE custom_bot_statuses SET gateway_reconnects = gateway_reconnects + 1, updated_at = $2 WHERE custom_bot_id = $1;
+
+-- name: SetCustomBotGatewayLatency :exec
+UPDATE custom_bot_statuses SET gateway_latency_ms = $2, updated_at = $3 WHERE custom_bot_id = $1;
+
+-- name: SetCustomBotLastInteraction :exec
+INSERT INTO custom_bot_statuses (custom_bot_id, last_interaction_at, updated_at) VALUES ($1, $2, $2) 
+ON CONFLICT (custom_bot_id) DO UPDATE SET last_interaction_at = EXCLUDED.last_interaction_at, updated_at = EXCLUDED.updated_at;
+
+-- name: SetCustomBotLastSignatureFailure :exec
+INSERT INTO custom_bot_statuses (custom_bot_id, last_signature_failure_at, updated_at) VALUES ($1, $2, $2) 
+ON CONFLICT (custom_bot_id) DO UPDATE SET last_signature_failure_at = EXCLUDED.last_signature_failure_at, updated_at = EXCLUDED.updated_at;
@@ -9,6 +9,9 @@ UPDATE custom_bots SET gateway_status = $2, gateway_activity_type = $3, gateway_
 -- name: UpdateCustomBotUser :one
 UPDATE custom_bots SET user_name = $2, user_discriminator = $3, user_avatar = $4 WHERE guild_id = $1 RETURNING *;
 
+-- name: UpdateCustomBotToken :execrows
+UPDATE custom_bots SET token = @new_token WHERE id = @id AND token = @old_token;
+
 -- name: UpdateCustomBotTokenInvalid :one
 UPDATE custom_bots SET token_invalid = $2 WHERE guild_id = $1 RETURNING *;
 
@@ -0,0 +1,13 @@
+-- name: InsertCustomCommandDeployment :one
+INSERT INTO custom_command_deployments (id, guild_id, application_id, user_id, commands, changes, rollback_of, created_at, partial) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+
+-- name: GetCustomCommandDeployment :one
+SELECT * FROM custom_command_deployments WHERE id = $1 AND guild_id = $2;
+
+-- name: GetCustomCommandDeployments :many
+SELECT * FROM custom_command_deployments WHERE guild_id = $1 ORDER BY created_at DESC LIMIT $2;
+
+-- name: DeleteOldCustomCommandDeployments :exec
+DELETE FROM custom_command_deployments WHERE guild_id = $1 AND id NOT IN (
+    SELECT id FROM custom_command_deployments WHERE guild_id = $1 ORDER BY created_at DESC LIMIT $2
+);
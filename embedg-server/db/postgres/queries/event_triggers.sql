@@ -0,0 +1,20 @@
+-- name: GetEventTriggers :many
+SELECT * FROM event_triggers WHERE guild_id = $1 ORDER BY created_at;
+
+-- name: GetEventTrigger :one
+SELECT * FROM event_triggers WHERE id = $1 AND guild_id = $2;
+
+-- name: GetEnabledEventTriggers :many
+SELECT * FROM event_triggers WHERE guild_id = $1 AND event = $2 AND enabled = true ORDER BY created_at;
+
+-- name: CountEventTriggers :one
+SELECT COUNT(*) FROM event_triggers WHERE guild_id = $1;
+
+-- name: InsertEventTrigger :one
+INSERT INTO event_triggers (id, creator_id, guild_id, event, name, saved_message_id, channel_id, actions, derived_permissions, enabled, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING *;
+
+-- name: UpdateEventTrigger :one
+UPDATE event_triggers SET event = $3, name = $4, saved_message_id = $5, channel_id = $6, actions = $7, derived_permissions = $8, enabled = $9, updated_at = $10 WHERE id = $1 AND guild_id = $2 RETURNING *;
+
+-- name: DeleteEventTrigger :one
+DELETE FROM event_triggers WHERE id = $1 AND guild_id = $2 RETURNING *;
@@ -1,5 +1,5 @@
 -- name: GetKVEntry :one
-SELECT * FROM kv_entries WHERE key = $1 AND guild_id = $2;
+SELECT * FROM kv_entries WHERE key = $1 AND guild_id = $2 AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC');
 
 -- name: SetKVEntry :exec
 INSERT INTO kv_entries (
@@ -39,16 +39,165 @@ INSERT INTO kv_entries (
     $6
 ) ON CONFLICT (key, guild_id)
 DO UPDATE SET 
-    value = kv_entries.value::int + EXCLUDED.value::int, 
-    expires_at = EXCLUDED.expires_at, 
+    value = CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN EXCLUDED.value 
+        ELSE (kv_entries.value::int + EXCLUDED.value::int)::text 
+    END, 
+    expires_at = CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN EXCLUDED.expires_at 
+        ELSE COALESCE(EXCLUDED.expires_at, kv_entries.expires_at) 
+    END, 
     updated_at = EXCLUDED.updated_at
 RETURNING *;
 
+-- name: SetKVEntryExpiresAt :one
+UPDATE kv_entries SET expires_at = $3, updated_at = $4 WHERE key = $1 AND guild_id = $2 AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC') RETURNING *;
+
 -- name: DeleteKVEntry :one
 DELETE FROM kv_entries WHERE key = $1 AND guild_id = $2 RETURNING *;
 
 -- name: SearchKVEntries :many
-SELECT * FROM kv_entries WHERE key LIKE $1 AND guild_id = $2;
+SELECT * FROM kv_entries WHERE key LIKE $1 AND guild_id = $2 AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC');
+
+-- name: SearchKVEntryKeys :many
+SELECT key FROM kv_entries WHERE key LIKE @key AND guild_id = @guild_id AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC') ORDER BY key LIMIT @max_keys;
 
 -- name: CountKVEntries :one
-SELECT COUNT(*) FROM kv_entries WHERE guild_id = $1;
\ No newline at end of file
+SELECT COUNT(*) FROM kv_entries WHERE guild_id = $1 AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC');
+
+-- name: ListKVEntries :many
+SELECT * FROM kv_entries WHERE key LIKE @key AND guild_id = @guild_id AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC') ORDER BY key LIMIT @max_entries OFFSET @skip_entries;
+
+-- name: CountExistingKVEntries :one
+SELECT COUNT(*) FROM kv_entries WHERE guild_id = @guild_id AND key = ANY(@keys::TEXT[]) AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC');
+
+-- name: CountMatchingKVEntries :one
+SELECT COUNT(*) FROM kv_entries WHERE key LIKE @key AND guild_id = @guild_id AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC');
+
+-- name: DeleteExpiredKVEntries :execrows
+DELETE FROM kv_entries WHERE (key, guild_id) IN (
+    SELECT key, guild_id FROM kv_entries WHERE expires_at <= $1 LIMIT $2
+);
+
+-- name: PushKVEntryList :one
+INSERT INTO kv_entries (
+    key, 
+    guild_id, 
+    value, 
+    expires_at, 
+    created_at, 
+    updated_at
+) VALUES (
+    $1, 
+    $2, 
+    $3, 
+    NULL, 
+    $4, 
+    $5
+) ON CONFLICT (key, guild_id)
+DO UPDATE SET 
+    value = (CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN '[]'::jsonb 
+        ELSE kv_entries.value::jsonb 
+    END || EXCLUDED.value::jsonb)::text, 
+    expires_at = CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN NULL 
+        ELSE kv_entries.expires_at 
+    END, 
+    updated_at = EXCLUDED.updated_at
+WHERE CASE 
+    WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN true 
+    ELSE jsonb_typeof(kv_entries.value::jsonb) = 'array' 
+END
+RETURNING *;
+
+-- name: AddKVEntrySetItem :one
+INSERT INTO kv_entries (
+    key, 
+    guild_id, 
+    value, 
+    expires_at, 
+    created_at, 
+    updated_at
+) VALUES (
+    $1, 
+    $2, 
+    $3, 
+    NULL, 
+    $4, 
+    $5
+) ON CONFLICT (key, guild_id)
+DO UPDATE SET 
+    value = (CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN '[]'::jsonb 
+        ELSE kv_entries.value::jsonb 
+    END || EXCLUDED.value::jsonb)::text, 
+    expires_at = CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN NULL 
+        ELSE kv_entries.expires_at 
+    END, 
+    updated_at = EXCLUDED.updated_at
+WHERE CASE 
+    WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN true 
+    WHEN jsonb_typeof(kv_entries.value::jsonb) <> 'array' THEN false 
+    ELSE NOT EXISTS (
+        SELECT 1 FROM jsonb_array_elements(kv_entries.value::jsonb) AS e(item) WHERE e.item = EXCLUDED.value::jsonb -> 0
+    ) 
+END
+RETURNING *;
+
+-- name: RemoveKVEntryListItem :one
+UPDATE kv_entries SET 
+    value = (
+        SELECT COALESCE(jsonb_agg(e.item ORDER BY e.idx), '[]'::jsonb) 
+        FROM jsonb_array_elements(kv_entries.value::jsonb) WITH ORDINALITY AS e(item, idx) 
+        WHERE e.item <> @item::jsonb
+    )::text, 
+    updated_at = @updated_at
+WHERE key = @key AND guild_id = @guild_id 
+    AND (expires_at IS NULL OR expires_at > NOW() AT TIME ZONE 'UTC') 
+    AND jsonb_typeof(value::jsonb) = 'array'
+RETURNING *;
+
+-- name: KVEntryListContains :one
+SELECT EXISTS (
+    SELECT 1 FROM kv_entries, jsonb_array_elements(CASE 
+        WHEN jsonb_typeof(kv_entries.value::jsonb) = 'array' THEN kv_entries.value::jsonb 
+        ELSE '[]'::jsonb 
+    END) AS e(item)
+    WHERE kv_entries.key = @key AND kv_entries.guild_id = @guild_id 
+        AND (kv_entries.expires_at IS NULL OR kv_entries.expires_at > NOW() AT TIME ZONE 'UTC') 
+        AND e.item = @item::jsonb
+);
+
+-- name: SetKVEntryMapField :one
+INSERT INTO kv_entries (
+    key, 
+    guild_id, 
+    value, 
+    expires_at, 
+    created_at, 
+    updated_at
+) VALUES (
+    $1, 
+    $2, 
+    $3, 
+    NULL, 
+    $4, 
+    $5
+) ON CONFLICT (key, guild_id)
+DO UPDATE SET 
+    value = (CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN '{}'::jsonb 
+        ELSE kv_entries.value::jsonb 
+    END || EXCLUDED.value::jsonb)::text, 
+    expires_at = CASE 
+        WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN NULL 
+        ELSE kv_entries.expires_at 
+    END, 
+    updated_at = EXCLUDED.updated_at
+WHERE CASE 
+    WHEN kv_entries.expires_at <= NOW() AT TIME ZONE 'UTC' THEN true 
+    ELSE jsonb_typeof(kv_entries.value::jsonb) = 'object' 
+END
+RETURNING *;
\ No newline at end of file
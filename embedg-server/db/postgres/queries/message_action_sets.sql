@@ -1,5 +1,5 @@
 -- name: InsertMessageActionSet :one
-INSERT INTO message_action_sets (id, message_id, set_id, actions, derived_permissions, ephemeral) VALUES ($1, $2, $3, $4, $5, $6) RETURNING *;
+INSERT INTO message_action_sets (id, message_id, set_id, actions, derived_permissions, ephemeral, guild_id) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *;
 
 -- name: GetMessageActionSet :one
 SELECT * FROM message_action_sets WHERE message_id = $1 AND set_id = $2;
@@ -9,3 +9,12 @@ SELECT * FROM message_action_sets WHERE message_id = $1;
 
 -- name: DeleteMessageActionSetsForMessage :exec
 DELETE FROM message_action_sets WHERE message_id = $1;
+
+-- name: GetMessageActionSetsPage :many
+SELECT * FROM message_action_sets WHERE id > $1 ORDER BY id LIMIT $2;
+
+-- name: GetMessageActionSetsForGuildPage :many
+SELECT * FROM message_action_sets WHERE guild_id = $1 AND id > $2 ORDER BY id LIMIT $3;
+
+-- name: UpdateMessageActionSetActions :exec
+UPDATE message_action_sets SET actions = $2 WHERE id = $1;
@@ -0,0 +1,13 @@
+-- name: GetSavedMessageVariants :many
+SELECT * FROM saved_message_variants WHERE saved_message_id = $1 ORDER BY locale;
+
+-- name: GetSavedMessageVariant :one
+SELECT * FROM saved_message_variants WHERE saved_message_id = $1 AND locale = $2;
+
+-- name: UpsertSavedMessageVariant :one
+INSERT INTO saved_message_variants (saved_message_id, locale, data, updated_at) VALUES ($1, $2, $3, $4) 
+ON CONFLICT (saved_message_id, locale) DO UPDATE SET data = EXCLUDED.data, updated_at = EXCLUDED.updated_at 
+RETURNING *;
+
+-- name: DeleteSavedMessageVariant :execrows
+DELETE FROM saved_message_variants WHERE saved_message_id = $1 AND locale = $2;
@@ -20,4 +20,10 @@ SELECT * FROM saved_messages WHERE creator_id = $1 AND guild_id IS NULL ORDER BY
 SELECT * FROM saved_messages WHERE guild_id = $1 ORDER BY updated_at DESC;
 
 -- name: GetSavedMessageForGuild :one
-SELECT * FROM saved_messages WHERE guild_id = $1 AND id = $2;
\ No newline at end of file
+SELECT * FROM saved_messages WHERE guild_id = $1 AND id = $2;
+
+-- name: GetSavedMessagesPage :many
+SELECT * FROM saved_messages WHERE id > $1 ORDER BY id LIMIT $2;
+
+-- name: UpdateSavedMessageData :exec
+UPDATE saved_messages SET data = $2 WHERE id = $1;
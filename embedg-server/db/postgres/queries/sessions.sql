@@ -8,4 +8,10 @@ SELECT * FROM sessions WHERE token_hash = $1;
 DELETE FROM sessions WHERE token_hash = $1;
 
 -- name: GetSessionsForUser :many
-SELECT * FROM sessions WHERE user_id = $1;
\ No newline at end of file
+SELECT * FROM sessions WHERE user_id = $1;
+
+-- name: GetSessionsPage :many
+SELECT * FROM sessions WHERE token_hash > $1 ORDER BY token_hash LIMIT $2;
+
+-- name: UpdateSessionAccessToken :execrows
+UPDATE sessions SET access_token = @new_access_token WHERE token_hash = @token_hash AND access_token = @old_access_token;
@@ -0,0 +1,13 @@
+-- name: GetTemplateHTTPHosts :many
+SELECT * FROM template_http_hosts WHERE guild_id = $1 ORDER BY host;
+
+-- name: CountTemplateHTTPHosts :one
+SELECT COUNT(*) FROM template_http_hosts WHERE guild_id = $1;
+
+-- name: UpsertTemplateHTTPHost :one
+INSERT INTO template_http_hosts (guild_id, host, created_at) VALUES ($1, $2, $3) 
+ON CONFLICT (guild_id, host) DO UPDATE SET host = EXCLUDED.host 
+RETURNING *;
+
+-- name: DeleteTemplateHTTPHost :execrows
+DELETE FROM template_http_hosts WHERE guild_id = $1 AND host = $2;
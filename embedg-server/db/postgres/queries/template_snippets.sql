@@ -0,0 +1,16 @@
+-- name: GetTemplateSnippets :many
+SELECT * FROM template_snippets WHERE guild_id = $1 ORDER BY name;
+
+-- name: GetTemplateSnippet :one
+SELECT * FROM template_snippets WHERE guild_id = $1 AND name = $2;
+
+-- name: CountTemplateSnippets :one
+SELECT COUNT(*) FROM template_snippets WHERE guild_id = $1;
+
+-- name: UpsertTemplateSnippet :one
+INSERT INTO template_snippets (guild_id, name, content, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) 
+ON CONFLICT (guild_id, name) DO UPDATE SET content = EXCLUDED.content, updated_at = EXCLUDED.updated_at 
+RETURNING *;
+
+-- name: DeleteTemplateSnippet :execrows
+DELETE FROM template_snippets WHERE guild_id = $1 AND name = $2;
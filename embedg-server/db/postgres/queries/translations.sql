@@ -0,0 +1,16 @@
+-- name: GetGuildTranslations :many
+SELECT * FROM guild_translations WHERE guild_id = $1 ORDER BY locale, key;
+
+-- name: GetGuildTranslationsForLocales :many
+SELECT * FROM guild_translations WHERE guild_id = @guild_id AND locale = ANY(@locales::TEXT[]);
+
+-- name: CountGuildTranslations :one
+SELECT COUNT(*) FROM guild_translations WHERE guild_id = $1;
+
+-- name: UpsertGuildTranslation :one
+INSERT INTO guild_translations (guild_id, locale, key, value, updated_at) VALUES ($1, $2, $3, $4, $5) 
+ON CONFLICT (guild_id, locale, key) DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at 
+RETURNING *;
+
+-- name: DeleteGuildTranslationsForLocale :execrows
+DELETE FROM guild_translations WHERE guild_id = $1 AND locale = $2;
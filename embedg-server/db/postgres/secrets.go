@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+)
+
+const secretRotationBatchSize = 1000
+
+type SecretRotation struct {
+	CustomBots int
+	Sessions   int
+	Failed     int
+}
+
+// RotateSecrets encrypts all secrets that are stored in plaintext or with an old master key with the primary master key.
+// Rows that were changed concurrently are skipped because they have already been written with the primary key.
+// Rows that can't be decrypted with any of the configured keys are counted as failed.
+func (s *PostgresStore) RotateSecrets(ctx context.Context) (SecretRotation, error) {
+	var res SecretRotation
+
+	if !s.keyring.Enabled() {
+		return res, errors.New("no encryption keys are configured")
+	}
+
+	customBots, err := s.Q.GetCustomBots(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	for _, customBot := range customBots {
+		if !s.keyring.NeedsRotation(customBot.Token) {
+			continue
+		}
+
+		token, err := s.rotateSecret(customBot.Token)
+		if err != nil {
+			res.Failed++
+			continue
+		}
+
+		updated, err := s.Q.UpdateCustomBotToken(ctx, pgmodel.UpdateCustomBotTokenParams{
+			NewToken: token,
+			ID:       customBot.ID,
+			OldToken: customBot.Token,
+		})
+		if err != nil {
+			return res, err
+		}
+		res.CustomBots += int(updated)
+	}
+
+	lastTokenHash := ""
+	for {
+		sessions, err := s.Q.GetSessionsPage(ctx, pgmodel.GetSessionsPageParams{
+			TokenHash: lastTokenHash,
+			Limit:     secretRotationBatchSize,
+		})
+		if err != nil {
+			return res, err
+		}
+		if len(sessions) == 0 {
+			break
+		}
+
+		for _, session := range sessions {
+			if !s.keyring.NeedsRotation(session.AccessToken) {
+				continue
+			}
+
+			accessToken, err := s.rotateSecret(session.AccessToken)
+			if err != nil {
+				res.Failed++
+				continue
+			}
+
+			updated, err := s.Q.UpdateSessionAccessToken(ctx, pgmodel.UpdateSessionAccessTokenParams{
+				NewAccessToken: accessToken,
+				TokenHash:      session.TokenHash,
+				OldAccessToken: session.AccessToken,
+			})
+			if err != nil {
+				return res, err
+			}
+			res.Sessions += int(updated)
+		}
+
+		lastTokenHash = sessions[len(sessions)-1].TokenHash
+	}
+
+	return res, nil
+}
+
+func (s *PostgresStore) rotateSecret(value string) (string, error) {
+	plaintext, err := s.keyring.Decrypt(value)
+	if err != nil {
+		return "", err
+	}
+	return s.keyring.Encrypt(plaintext)
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+)
+
+// The Discord access token of sessions is encrypted at rest, these methods should be used instead of the queries
+// whenever the access token is read or written.
+
+func (s *PostgresStore) GetSession(ctx context.Context, tokenHash string) (pgmodel.Session, error) {
+	row, err := s.Q.GetSession(ctx, tokenHash)
+	if err != nil {
+		return pgmodel.Session{}, err
+	}
+	return s.decryptSession(row)
+}
+
+func (s *PostgresStore) GetSessionsForUser(ctx context.Context, userID string) ([]pgmodel.Session, error) {
+	rows, err := s.Q.GetSessionsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, row := range rows {
+		rows[i], err = s.decryptSession(row)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+func (s *PostgresStore) InsertSession(ctx context.Context, params pgmodel.InsertSessionParams) (pgmodel.Session, error) {
+	accessToken, err := s.keyring.Encrypt(params.AccessToken)
+	if err != nil {
+		return pgmodel.Session{}, fmt.Errorf("failed to encrypt session access token: %w", err)
+	}
+	params.AccessToken = accessToken
+
+	row, err := s.Q.InsertSession(ctx, params)
+	if err != nil {
+		return pgmodel.Session{}, err
+	}
+	return s.decryptSession(row)
+}
+
+func (s *PostgresStore) decryptSession(row pgmodel.Session) (pgmodel.Session, error) {
+	accessToken, err := s.keyring.Decrypt(row.AccessToken)
+	if err != nil {
+		return pgmodel.Session{}, fmt.Errorf("failed to decrypt session access token: %w", err)
+	}
+	row.AccessToken = accessToken
+	return row, nil
+}
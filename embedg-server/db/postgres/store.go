@@ -8,6 +8,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/merlinfuchs/embed-generator/embedg-server/encryption"
 	"github.com/spf13/viper"
 )
 
@@ -26,8 +27,9 @@ func BuildConnectionDSN() string {
 }
 
 type PostgresStore struct {
-	db *sqlx.DB
-	Q  *pgmodel.Queries
+	db      *sqlx.DB
+	keyring *encryption.Keyring
+	Q       *pgmodel.Queries
 }
 
 func NewPostgresStore() *PostgresStore {
@@ -40,8 +42,14 @@ func NewPostgresStore() *PostgresStore {
 	db.SetMaxOpenConns(80)
 	db.SetConnMaxLifetime(time.Hour * 1)
 
+	keyring, err := encryption.KeyringFromConfig()
+	if err != nil {
+		log.Fatalf("Failed to load encryption keys: %v", err)
+	}
+
 	return &PostgresStore{
-		db: db,
-		Q:  pgmodel.New(db),
+		db:      db,
+		keyring: keyring,
+		Q:       pgmodel.New(db),
 	}
 }
@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/model"
+)
+
+func (s *PostgresStore) GetTemplateHTTPHosts(ctx context.Context, guildID string) ([]model.TemplateHTTPHost, error) {
+	rows, err := s.Q.GetTemplateHTTPHosts(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.TemplateHTTPHost, len(rows))
+	for i, row := range rows {
+		res[i] = model.TemplateHTTPHost{
+			GuildID:   row.GuildID,
+			Host:      row.Host,
+			CreatedAt: row.CreatedAt,
+		}
+	}
+
+	return res, nil
+}
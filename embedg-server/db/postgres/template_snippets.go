@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/merlinfuchs/embed-generator/embedg-server/model"
+	"github.com/merlinfuchs/embed-generator/embedg-server/store"
+)
+
+func (s *PostgresStore) GetTemplateSnippet(ctx context.Context, guildID string, name string) (model.TemplateSnippet, error) {
+	row, err := s.Q.GetTemplateSnippet(ctx, pgmodel.GetTemplateSnippetParams{
+		GuildID: guildID,
+		Name:    name,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.TemplateSnippet{}, store.ErrNotFound
+		}
+		return model.TemplateSnippet{}, err
+	}
+
+	return model.TemplateSnippet{
+		GuildID:   row.GuildID,
+		Name:      row.Name,
+		Content:   row.Content,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}, nil
+}
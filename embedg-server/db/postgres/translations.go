@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/merlinfuchs/embed-generator/embedg-server/model"
+)
+
+func (s *PostgresStore) GetTranslationsForLocales(ctx context.Context, guildID string, locales []string) ([]model.Translation, error) {
+	rows, err := s.Q.GetGuildTranslationsForLocales(ctx, pgmodel.GetGuildTranslationsForLocalesParams{
+		GuildID: guildID,
+		Locales: locales,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.Translation, len(rows))
+	for i, row := range rows {
+		res[i] = rowToTranslation(row)
+	}
+	return res, nil
+}
+
+// ReplaceTranslationsForLocale atomically replaces all translations of the guild for the locale with the given values.
+func (s *PostgresStore) ReplaceTranslationsForLocale(ctx context.Context, guildID string, locale string, values map[string]string) ([]model.Translation, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := s.Q.WithTx(tx)
+
+	_, err = q.DeleteGuildTranslationsForLocale(ctx, pgmodel.DeleteGuildTranslationsForLocaleParams{
+		GuildID: guildID,
+		Locale:  locale,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.Translation, 0, len(values))
+	for key, value := range values {
+		row, err := q.UpsertGuildTranslation(ctx, pgmodel.UpsertGuildTranslationParams{
+			GuildID:   guildID,
+			Locale:    locale,
+			Key:       key,
+			Value:     value,
+			UpdatedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, rowToTranslation(row))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return res, nil
+}
+
+func rowToTranslation(row pgmodel.GuildTranslation) model.Translation {
+	return model.Translation{
+		GuildID:   row.GuildID,
+		Locale:    row.Locale,
+		Key:       row.Key,
+		Value:     row.Value,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
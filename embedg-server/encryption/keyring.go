@@ -0,0 +1,209 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// prefix marks encrypted values, values without it are legacy plaintext values.
+const prefix = "enc:v1:"
+
+const keySize = 32
+
+var ErrNoKeys = errors.New("value is encrypted but no encryption keys are configured")
+
+// Keyring encrypts secrets with envelope encryption.
+// Every value is encrypted with its own random data key which is in turn encrypted with the primary master key.
+// Older master keys are only used for decrypting values that haven't been rotated yet.
+// A nil keyring stores values in plaintext.
+type Keyring struct {
+	primaryID string
+	keys      map[string]cipher.AEAD
+}
+
+// NewKeyring creates a keyring from master keys in the format "id:base64key", the first key is the primary key.
+func NewKeyring(masterKeys []string) (*Keyring, error) {
+	if len(masterKeys) == 0 {
+		return nil, nil
+	}
+
+	k := &Keyring{
+		keys: make(map[string]cipher.AEAD, len(masterKeys)),
+	}
+
+	for i, masterKey := range masterKeys {
+		id, encoded, ok := strings.Cut(masterKey, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("master key %d must be in the format id:base64key", i)
+		}
+		if _, exists := k.keys[id]; exists {
+			return nil, fmt.Errorf("master key %s is configured more than once", id)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("master key %s is not valid base64: %w", id, err)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("master key %s must be %d bytes long", id, keySize)
+		}
+
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+
+		k.keys[id] = aead
+		if i == 0 {
+			k.primaryID = id
+		}
+	}
+
+	return k, nil
+}
+
+// KeyringFromConfig creates a keyring from the encryption.master_keys config value.
+func KeyringFromConfig() (*Keyring, error) {
+	return NewKeyring(viper.GetStringSlice("encryption.master_keys"))
+}
+
+// GenerateMasterKey returns a new random master key in the format that is expected by NewKeyring.
+func GenerateMasterKey(id string) (string, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return id + ":" + base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Enabled returns whether values are encrypted when they are stored.
+func (k *Keyring) Enabled() bool {
+	return k != nil
+}
+
+// Encrypt encrypts the value with a new data key, the value is returned unchanged when encryption is disabled.
+func (k *Keyring) Encrypt(value string) (string, error) {
+	if k == nil {
+		return value, nil
+	}
+
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	// The key ID is authenticated so that a value can't be decrypted with another master key
+	aad := []byte(k.primaryID)
+
+	wrappedKey, err := seal(k.keys[k.primaryID], dataKey, aad)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(dataAEAD, []byte(value), aad)
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + k.primaryID + ":" +
+		base64.RawURLEncoding.EncodeToString(wrappedKey) + ":" +
+		base64.RawURLEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt decrypts a value that was encrypted with any of the master keys, plaintext values are returned unchanged.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if k == nil {
+		return "", ErrNoKeys
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("encrypted value is malformed")
+	}
+	keyID := parts[0]
+
+	masterAEAD, ok := k.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("value is encrypted with unknown master key %s", keyID)
+	}
+
+	wrappedKey, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("encrypted value is malformed: %w", err)
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("encrypted value is malformed: %w", err)
+	}
+
+	aad := []byte(keyID)
+
+	dataKey, err := open(masterAEAD, wrappedKey, aad)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dataAEAD, ciphertext, aad)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsRotation returns whether the value is stored in plaintext or encrypted with a master key that isn't the primary key.
+func (k *Keyring) NeedsRotation(value string) bool {
+	if k == nil {
+		return false
+	}
+	return !strings.HasPrefix(value, prefix+k.primaryID+":")
+}
+
+// IsEncrypted returns whether the value was encrypted by a keyring.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts the plaintext and prepends the random nonce.
+func seal(aead cipher.AEAD, plaintext []byte, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, ciphertext []byte, aad []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, aad)
+}
@@ -11,6 +11,9 @@ func Setup() *cobra.Command {
 	}
 
 	adminRootCMD.AddCommand(impersonateCMD())
+	adminRootCMD.AddCommand(convertVariablesCMD())
+	adminRootCMD.AddCommand(generateKeyCMD())
+	adminRootCMD.AddCommand(rotateKeysCMD())
 
 	return adminRootCMD
 }
@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres"
+	"github.com/merlinfuchs/embed-generator/embedg-server/variables_migration"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func convertVariablesCMD() *cobra.Command {
+	convertVariablesCMD := &cobra.Command{
+		Use:   "convert-variables",
+		Short: "Convert legacy {variable} placeholders in saved messages and actions to templates (dry run unless --apply is passed)",
+		Run: func(cmd *cobra.Command, args []string) {
+			guildID, _ := cmd.Flags().GetString("guild_id")
+			apply, _ := cmd.Flags().GetBool("apply")
+
+			if err := ConvertVariables(guildID, apply); err != nil {
+				log.Error().Err(err).Msg("Failed to convert variables")
+			}
+		},
+	}
+	convertVariablesCMD.Flags().String("guild_id", "", "Only convert the saved messages and custom commands of this guild")
+	convertVariablesCMD.Flags().Bool("apply", false, "Write the converted data to the database")
+
+	return convertVariablesCMD
+}
+
+func ConvertVariables(guildID string, apply bool) error {
+	pg := postgres.NewPostgresStore()
+	migrator := variables_migration.New(pg)
+
+	var total, converted int
+	printChange := func(change variables_migration.Change) {
+		total++
+		converted += change.Converted
+
+		line := fmt.Sprintf("%s %s (%s): %d converted", change.Type, change.ID, change.Name, change.Converted)
+		if len(change.Unsupported) != 0 {
+			line += fmt.Sprintf(", unsupported: %s", strings.Join(change.Unsupported, ", "))
+		}
+		fmt.Println(line)
+	}
+
+	if guildID != "" {
+		changes, err := migrator.ConvertGuild(context.Background(), guildID, apply)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			printChange(change)
+		}
+	} else {
+		if err := migrator.ConvertAll(context.Background(), apply, printChange); err != nil {
+			return err
+		}
+	}
+
+	if apply {
+		fmt.Printf("Converted %d variables in %d rows\n", converted, total)
+	} else {
+		fmt.Printf("Dry run: would convert %d variables in %d rows, pass --apply to write the changes\n", converted, total)
+	}
+	return nil
+}
@@ -33,7 +33,7 @@ func CreateSessionForUser(userID string) (string, error) {
 	pg := postgres.NewPostgresStore()
 	sessionManager := session.New(pg)
 
-	sessions, err := pg.Q.GetSessionsForUser(context.Background(), userID)
+	sessions, err := pg.GetSessionsForUser(context.Background(), userID)
 	if err != nil {
 		return "", err
 	}
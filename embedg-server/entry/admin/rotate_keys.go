@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres"
+	"github.com/merlinfuchs/embed-generator/embedg-server/encryption"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func generateKeyCMD() *cobra.Command {
+	generateKeyCMD := &cobra.Command{
+		Use:   "generate-key",
+		Short: "Generate a new master key that can be added to encryption.master_keys",
+		Run: func(cmd *cobra.Command, args []string) {
+			id, _ := cmd.Flags().GetString("id")
+
+			key, err := encryption.GenerateMasterKey(id)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to generate master key")
+				return
+			}
+
+			fmt.Println(key)
+		},
+	}
+	generateKeyCMD.Flags().String("id", "", "ID of the key which is stored alongside encrypted values")
+	generateKeyCMD.MarkFlagRequired("id")
+
+	return generateKeyCMD
+}
+
+func rotateKeysCMD() *cobra.Command {
+	rotateKeysCMD := &cobra.Command{
+		Use:   "rotate-keys",
+		Short: "Re-encrypt all custom bot tokens and session access tokens with the first key of encryption.master_keys",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := RotateKeys(); err != nil {
+				log.Error().Err(err).Msg("Failed to rotate keys")
+			}
+		},
+	}
+
+	return rotateKeysCMD
+}
+
+func RotateKeys() error {
+	pg := postgres.NewPostgresStore()
+
+	res, err := pg.RotateSecrets(context.Background())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Re-encrypted %d custom bot tokens and %d session access tokens\n", res.CustomBots, res.Sessions)
+	if res.Failed != 0 {
+		fmt.Printf("%d values couldn't be decrypted with any of the configured keys, keep the old keys until they have been resolved\n", res.Failed)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"os"
 
 	"github.com/golang-migrate/migrate/v4"
@@ -39,10 +40,11 @@ func Migrate(storeName string, operation string, opts MigrateOpts) {
 	l.Debug().Msg("Starting migration")
 
 	var migrater Migrater
+	var pg *postgres.PostgresStore
 
 	switch storeName {
 	case "postgres":
-		pg := postgres.NewPostgresStore()
+		pg = postgres.NewPostgresStore()
 		pgMigrater, err := pg.GetMigrater()
 		if err != nil {
 			l.Error().Err(err).Msg("Failed to get migrater")
@@ -64,6 +66,9 @@ func Migrate(storeName string, operation string, opts MigrateOpts) {
 	switch operation {
 	case "up":
 		err = migrater.Up()
+		if err == nil || err == migrate.ErrNoChange {
+			encryptLegacySecrets(l, pg)
+		}
 	case "down":
 		err = migrater.Down()
 	case "list":
@@ -110,3 +115,23 @@ func Migrate(storeName string, operation string, opts MigrateOpts) {
 
 	l.Debug().Msg("Migration end")
 }
+
+// encryptLegacySecrets encrypts secrets that were stored before encryption was configured.
+// This can't be done in a SQL migration because the master keys are only known to the application.
+func encryptLegacySecrets(l zerolog.Logger, pg *postgres.PostgresStore) {
+	if pg == nil || len(viper.GetStringSlice("encryption.master_keys")) == 0 {
+		return
+	}
+
+	res, err := pg.RotateSecrets(context.Background())
+	if err != nil {
+		l.Error().Err(err).Msg("Failed to encrypt legacy secrets")
+		return
+	}
+
+	l.Info().
+		Int("custom_bots", res.CustomBots).
+		Int("sessions", res.Sessions).
+		Int("failed", res.Failed).
+		Msg("Encrypted legacy secrets")
+}
@@ -0,0 +1,263 @@
+package event_triggers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/merlinfuchs/discordgo"
+	"github.com/merlinfuchs/embed-generator/embedg-server/actions"
+	"github.com/merlinfuchs/embed-generator/embedg-server/actions/parser"
+	"github.com/merlinfuchs/embed-generator/embedg-server/actions/template"
+	"github.com/merlinfuchs/embed-generator/embedg-server/bot"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/merlinfuchs/embed-generator/embedg-server/model"
+	"github.com/merlinfuchs/embed-generator/embedg-server/store"
+	"github.com/rs/zerolog/log"
+)
+
+// EventTriggerManager sends the saved message and runs the actions of event triggers when their event happens.
+type EventTriggerManager struct {
+	pg           *postgres.PostgresStore
+	bot          *bot.Bot
+	actionParser *parser.ActionParser
+	planStore    store.PlanStore
+}
+
+func NewEventTriggerManager(
+	pg *postgres.PostgresStore,
+	actionParser *parser.ActionParser,
+	bot *bot.Bot,
+	planStore store.PlanStore,
+) *EventTriggerManager {
+	return &EventTriggerManager{
+		pg:           pg,
+		bot:          bot,
+		actionParser: actionParser,
+		planStore:    planStore,
+	}
+}
+
+// HandleMemberEvent runs the enabled triggers of the guild for the event, s is the session of the custom bot that received the event.
+func (m *EventTriggerManager) HandleMemberEvent(s *discordgo.Session, event string, guildID string, member *discordgo.Member) {
+	ctx := context.Background()
+
+	triggers, err := m.pg.Q.GetEnabledEventTriggers(ctx, pgmodel.GetEnabledEventTriggersParams{
+		GuildID: guildID,
+		Event:   event,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("guild_id", guildID).Msg("Failed to retrieve event triggers")
+		return
+	}
+	if len(triggers) == 0 {
+		return
+	}
+
+	features, err := m.planStore.GetPlanFeaturesForGuild(ctx, guildID)
+	if err != nil {
+		log.Error().Err(err).Str("guild_id", guildID).Msg("Failed to retrieve plan features for event triggers")
+		return
+	}
+
+	for _, trigger := range triggers {
+		if err := m.RunEventTrigger(ctx, s, trigger, member, features); err != nil {
+			log.Error().Err(err).Str("event_trigger_id", trigger.ID).Msg("Failed to run event trigger")
+		}
+	}
+}
+
+// RunEventTrigger sends the saved message of the trigger and runs its actions for the member.
+func (m *EventTriggerManager) RunEventTrigger(
+	ctx context.Context,
+	s *discordgo.Session,
+	trigger pgmodel.EventTrigger,
+	member *discordgo.Member,
+	features model.PlanFeatures,
+) error {
+	derivedPerms := actions.ActionDerivedPermissions{}
+	if err := json.Unmarshal(trigger.DerivedPermissions, &derivedPerms); err != nil {
+		return fmt.Errorf("Failed to unmarshal permission context: %w", err)
+	}
+
+	templateCtx, cancel := context.WithTimeout(ctx, template.DefaultTimeout)
+	defer cancel()
+
+	templates := template.NewContext(
+		templateCtx, "EVENT_TRIGGER", features.MaxTemplateOps,
+		template.NewGuildProvider(m.bot.State, trigger.GuildID, nil),
+		template.NewChannelProvider(m.bot.State, trigger.ChannelID.String, nil),
+		template.NewMemberProvider(m.bot.State, trigger.GuildID, member),
+		template.NewKVProvider(trigger.GuildID, m.pg, features.MaxKVKeys),
+		template.NewEntityProvider(m.bot.State, m.bot.Rest, trigger.GuildID),
+		template.NewTranslationProvider(trigger.GuildID, m.pg, template.GuildLocale(m.bot.State, trigger.GuildID)),
+		template.NewSnippetProvider(trigger.GuildID, m.pg),
+		template.NewHTTPProvider(trigger.GuildID, m.pg, nil, features.HTTPRequests),
+	)
+
+	if trigger.SavedMessageID.Valid {
+		if err := m.sendMessage(ctx, s, trigger, member, templates, derivedPerms, features); err != nil {
+			return err
+		}
+	}
+
+	actionSet := actions.ActionSet{}
+	if err := json.Unmarshal(trigger.Actions, &actionSet); err != nil {
+		return fmt.Errorf("Failed to unmarshal action set: %w", err)
+	}
+
+	for _, action := range actionSet.Actions {
+		if err := m.runAction(ctx, s, trigger, member, templates, derivedPerms, action); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sendMessage sends the saved message of the trigger to the channel of the trigger or as a DM to the member.
+func (m *EventTriggerManager) sendMessage(
+	ctx context.Context,
+	s *discordgo.Session,
+	trigger pgmodel.EventTrigger,
+	member *discordgo.Member,
+	templates *template.TemplateContext,
+	derivedPerms actions.ActionDerivedPermissions,
+	features model.PlanFeatures,
+) error {
+	data, err := m.savedMessage(ctx, trigger.GuildID, trigger.SavedMessageID.String, templates)
+	if err != nil {
+		return err
+	}
+
+	if !trigger.ChannelID.Valid {
+		return sendDM(s, member.User.ID, data)
+	}
+
+	params := &discordgo.WebhookParams{
+		Content:         data.Content,
+		Username:        data.Username,
+		AvatarURL:       data.AvatarURL,
+		TTS:             data.TTS,
+		Embeds:          data.Embeds,
+		AllowedMentions: data.AllowedMentions,
+		Flags:           data.Flags,
+	}
+
+	params.Components, err = m.actionParser.ParseMessageComponents(data.Components, features.ComponentTypes)
+	if err != nil {
+		return fmt.Errorf("Invalid actions: %w", err)
+	}
+
+	msg, err := m.bot.SendMessageToChannel(ctx, trigger.ChannelID.String, params)
+	if err != nil {
+		return fmt.Errorf("Failed to send message: %w", err)
+	}
+
+	err = m.actionParser.CreateActionsForMessage(ctx, data.Actions, derivedPerms, trigger.GuildID, msg.ID, false)
+	if err != nil {
+		return fmt.Errorf("Failed to create actions for message: %w", err)
+	}
+
+	return nil
+}
+
+// runAction runs a single action of the trigger, only the actions that don't need an interaction are supported.
+func (m *EventTriggerManager) runAction(
+	ctx context.Context,
+	s *discordgo.Session,
+	trigger pgmodel.EventTrigger,
+	member *discordgo.Member,
+	templates *template.TemplateContext,
+	derivedPerms actions.ActionDerivedPermissions,
+	action actions.Action,
+) error {
+	switch action.Type {
+	case actions.ActionTypeAddRole, actions.ActionTypeRemoveRole, actions.ActionTypeToggleRole:
+		if !derivedPerms.CanManageRole(action.TargetID) {
+			return fmt.Errorf("The user that has created the trigger doesn't have permissions to manage the role %s", action.TargetID)
+		}
+
+		add := action.Type == actions.ActionTypeAddRole ||
+			(action.Type == actions.ActionTypeToggleRole && !slices.Contains(member.Roles, action.TargetID))
+
+		var err error
+		if add {
+			err = s.GuildMemberRoleAdd(trigger.GuildID, member.User.ID, action.TargetID, discordgo.WithContext(ctx))
+		} else {
+			err = s.GuildMemberRoleRemove(trigger.GuildID, member.User.ID, action.TargetID, discordgo.WithContext(ctx))
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to add or remove role %s: %w", action.TargetID, err)
+		}
+	case actions.ActionTypeTextDM:
+		content, err := templates.ParseAndExecute(action.Text)
+		if err != nil {
+			return fmt.Errorf("Failed to execute template: %w", err)
+		}
+
+		return sendDM(s, member.User.ID, &actions.MessageWithActions{Content: content})
+	case actions.ActionTypeSavedMessageDM:
+		data, err := m.savedMessage(ctx, trigger.GuildID, action.TargetID, templates)
+		if err != nil {
+			return err
+		}
+
+		return sendDM(s, member.User.ID, data)
+	}
+
+	return nil
+}
+
+// savedMessage returns the data of the saved message with its templates executed.
+func (m *EventTriggerManager) savedMessage(
+	ctx context.Context,
+	guildID string,
+	savedMessageID string,
+	templates *template.TemplateContext,
+) (*actions.MessageWithActions, error) {
+	savedMsg, err := m.pg.Q.GetSavedMessageForGuild(ctx, pgmodel.GetSavedMessageForGuildParams{
+		ID: savedMessageID,
+		GuildID: sql.NullString{
+			String: guildID,
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get saved message: %w", err)
+	}
+
+	data := &actions.MessageWithActions{}
+	if err := json.Unmarshal(savedMsg.Data, data); err != nil {
+		return nil, err
+	}
+
+	if err := templates.ParseAndExecuteMessage(data); err != nil {
+		return nil, fmt.Errorf("Failed to parse and execute message template: %w", err)
+	}
+
+	return data, nil
+}
+
+// sendDM sends the message to the user, components aren't supported in DMs.
+func sendDM(s *discordgo.Session, userID string, data *actions.MessageWithActions) error {
+	dmChannel, err := s.UserChannelCreate(userID)
+	if err != nil {
+		return fmt.Errorf("Failed to create DM channel: %w", err)
+	}
+
+	_, err = s.ChannelMessageSendComplex(dmChannel.ID, &discordgo.MessageSend{
+		Content:         data.Content,
+		Embeds:          data.Embeds,
+		AllowedMentions: data.AllowedMentions,
+		Flags:           data.Flags,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to send DM: %w", err)
+	}
+
+	return nil
+}
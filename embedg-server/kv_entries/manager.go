@@ -0,0 +1,60 @@
+package kv_entries
+
+import (
+	"context"
+	"time"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/store"
+	"github.com/rs/zerolog/log"
+)
+
+// expiredEntriesBatchSize limits how many rows are deleted in a single statement so the sweeper never holds long locks.
+const expiredEntriesBatchSize = 1000
+
+type KVEntryManager struct {
+	kvStore store.KVEntryStore
+}
+
+func NewKVEntryManager(kvStore store.KVEntryStore) *KVEntryManager {
+	m := &KVEntryManager{
+		kvStore: kvStore,
+	}
+
+	go m.lazyDeleteExpiredEntriesTask()
+
+	return m
+}
+
+func (m *KVEntryManager) lazyDeleteExpiredEntriesTask() {
+	for {
+		time.Sleep(1 * time.Minute)
+
+		deleted, err := m.DeleteExpiredEntries(context.Background())
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to delete expired KV entries")
+			continue
+		}
+
+		if deleted > 0 {
+			log.Info().Msgf("%d expired KV entries deleted", deleted)
+		}
+	}
+}
+
+// DeleteExpiredEntries deletes all expired entries in batches and returns the total number of deleted entries.
+func (m *KVEntryManager) DeleteExpiredEntries(ctx context.Context) (int, error) {
+	total := 0
+	now := time.Now().UTC()
+
+	for {
+		deleted, err := m.kvStore.DeleteExpiredKVEntries(ctx, now, expiredEntriesBatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		total += deleted
+		if deleted < expiredEntriesBatchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,417 @@
+package kv_entries
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/model"
+	"github.com/merlinfuchs/embed-generator/embedg-server/store"
+	"gopkg.in/guregu/null.v4"
+)
+
+// OverlayStore is a KV store that reads through to another store but keeps all writes in memory.
+// It's used to render templates without persisting any of their side effects.
+type OverlayStore struct {
+	sync.Mutex
+	base    store.KVEntryStore
+	entries map[string]*model.KVEntry // nil marks a deleted entry
+}
+
+func NewOverlayStore(base store.KVEntryStore, guildID string, values map[string]string) *OverlayStore {
+	s := &OverlayStore{
+		base:    base,
+		entries: make(map[string]*model.KVEntry, len(values)),
+	}
+
+	now := time.Now().UTC()
+	for key, value := range values {
+		s.entries[key] = &model.KVEntry{
+			Key:       key,
+			GuildID:   guildID,
+			Value:     value,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+
+	return s
+}
+
+func (s *OverlayStore) GetKVEntry(ctx context.Context, guildID string, key string) (model.KVEntry, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.get(ctx, guildID, key)
+}
+
+func (s *OverlayStore) get(ctx context.Context, guildID string, key string) (model.KVEntry, error) {
+	entry, ok := s.entries[key]
+	if !ok {
+		return s.base.GetKVEntry(ctx, guildID, key)
+	}
+
+	if entry == nil || entry.IsExpired(time.Now().UTC()) {
+		return model.KVEntry{}, store.ErrNotFound
+	}
+	return *entry, nil
+}
+
+func (s *OverlayStore) set(entry model.KVEntry) {
+	s.entries[entry.Key] = &entry
+}
+
+func (s *OverlayStore) SetKVEntry(ctx context.Context, entry model.KVEntry) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.set(entry)
+	return nil
+}
+
+func (s *OverlayStore) SetKVEntryExpiresAt(ctx context.Context, guildID string, key string, expiresAt null.Time) (model.KVEntry, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	entry, err := s.get(ctx, guildID, key)
+	if err != nil {
+		return model.KVEntry{}, err
+	}
+
+	entry.ExpiresAt = expiresAt
+	entry.UpdatedAt = time.Now().UTC()
+	s.set(entry)
+	return entry, nil
+}
+
+func (s *OverlayStore) IncreaseKVEntry(ctx context.Context, params model.KVEntryIncreaseParams) (model.KVEntry, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	entry, err := s.getOrCreate(ctx, params.GuildID, params.Key, "0", params.CreatedAt)
+	if err != nil {
+		return model.KVEntry{}, err
+	}
+
+	value, err := strconv.Atoi(entry.Value)
+	if err != nil {
+		return model.KVEntry{}, store.ErrWrongValueType
+	}
+
+	entry.Value = strconv.Itoa(value + params.Delta)
+	entry.UpdatedAt = params.UpdatedAt
+	s.set(entry)
+	return entry, nil
+}
+
+func (s *OverlayStore) DeleteKVEntry(ctx context.Context, guildID string, key string) (model.KVEntry, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	entry, err := s.get(ctx, guildID, key)
+	if err != nil {
+		return model.KVEntry{}, err
+	}
+
+	s.entries[key] = nil
+	return entry, nil
+}
+
+func (s *OverlayStore) SearchKVEntries(ctx context.Context, guildID string, pattern string) ([]model.KVEntry, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.search(ctx, guildID, pattern)
+}
+
+func (s *OverlayStore) search(ctx context.Context, guildID string, pattern string) ([]model.KVEntry, error) {
+	baseEntries, err := s.base.SearchKVEntries(ctx, guildID, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	matcher := likePatternToRegex(pattern)
+	now := time.Now().UTC()
+
+	res := make([]model.KVEntry, 0, len(baseEntries))
+	for _, entry := range baseEntries {
+		if _, ok := s.entries[entry.Key]; !ok {
+			res = append(res, entry)
+		}
+	}
+	for key, entry := range s.entries {
+		if entry != nil && !entry.IsExpired(now) && matcher.MatchString(key) {
+			res = append(res, *entry)
+		}
+	}
+
+	return res, nil
+}
+
+func (s *OverlayStore) ListKVEntries(ctx context.Context, guildID string, pattern string, limit int, offset int) ([]model.KVEntry, int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	entries, err := s.search(ctx, guildID, pattern)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	slices.SortFunc(entries, func(a, b model.KVEntry) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	total := len(entries)
+	return entries[min(offset, total):min(offset+limit, total)], total, nil
+}
+
+func (s *OverlayStore) ImportKVEntries(ctx context.Context, guildID string, entries []model.KVEntry, maxKeys int) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	created := 0
+	for _, entry := range entries {
+		if _, err := s.get(ctx, guildID, entry.Key); err != nil {
+			if err != store.ErrNotFound {
+				return 0, err
+			}
+			created++
+		}
+	}
+
+	if created > 0 {
+		existing, err := s.search(ctx, guildID, "%")
+		if err != nil {
+			return 0, err
+		}
+		if len(existing)+created > maxKeys {
+			return 0, store.ErrTooManyKeys
+		}
+	}
+
+	for _, entry := range entries {
+		entry.GuildID = guildID
+		s.set(entry)
+	}
+	return created, nil
+}
+
+func (s *OverlayStore) CountKVEntries(ctx context.Context, guildID string) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	entries, err := s.search(ctx, guildID, "%")
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+func (s *OverlayStore) DeleteExpiredKVEntries(ctx context.Context, before time.Time, limit int) (int, error) {
+	return 0, nil
+}
+
+func (s *OverlayStore) PushKVEntryList(ctx context.Context, params model.KVEntryCollectionParams) (model.KVEntry, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	entry, list, err := s.getList(ctx, params.GuildID, params.Key, params.CreatedAt)
+	if err != nil {
+		return model.KVEntry{}, err
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(params.Value, &items); err != nil {
+		return model.KVEntry{}, err
+	}
+
+	return s.setList(entry, append(list, items...), params.UpdatedAt)
+}
+
+func (s *OverlayStore) AddKVEntrySetItem(ctx context.Context, params model.KVEntryCollectionParams) (model.KVEntry, bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	entry, list, err := s.getList(ctx, params.GuildID, params.Key, params.CreatedAt)
+	if err != nil {
+		return model.KVEntry{}, false, err
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(params.Value, &items); err != nil || len(items) != 1 {
+		return model.KVEntry{}, false, store.ErrWrongValueType
+	}
+
+	if indexOfJSON(list, items[0]) != -1 {
+		return entry, false, nil
+	}
+
+	entry, err = s.setList(entry, append(list, items[0]), params.UpdatedAt)
+	return entry, err == nil, err
+}
+
+func (s *OverlayStore) RemoveKVEntryListItem(ctx context.Context, guildID string, key string, item json.RawMessage) (model.KVEntry, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	entry, err := s.get(ctx, guildID, key)
+	if err != nil {
+		return model.KVEntry{}, err
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal([]byte(entry.Value), &list); err != nil || list == nil {
+		return model.KVEntry{}, store.ErrWrongValueType
+	}
+
+	for {
+		index := indexOfJSON(list, item)
+		if index == -1 {
+			break
+		}
+		list = append(list[:index], list[index+1:]...)
+	}
+
+	return s.setList(entry, list, time.Now().UTC())
+}
+
+func (s *OverlayStore) KVEntryListContains(ctx context.Context, guildID string, key string, item json.RawMessage) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	entry, err := s.get(ctx, guildID, key)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal([]byte(entry.Value), &list); err != nil {
+		return false, nil
+	}
+
+	return indexOfJSON(list, item) != -1, nil
+}
+
+func (s *OverlayStore) SetKVEntryMapField(ctx context.Context, params model.KVEntryCollectionParams) (model.KVEntry, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	entry, err := s.getOrCreate(ctx, params.GuildID, params.Key, "{}", params.CreatedAt)
+	if err != nil {
+		return model.KVEntry{}, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(entry.Value), &fields); err != nil || fields == nil {
+		return model.KVEntry{}, store.ErrWrongValueType
+	}
+
+	var update map[string]json.RawMessage
+	if err := json.Unmarshal(params.Value, &update); err != nil {
+		return model.KVEntry{}, err
+	}
+	for field, value := range update {
+		fields[field] = value
+	}
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return model.KVEntry{}, err
+	}
+
+	entry.Value = string(raw)
+	entry.UpdatedAt = params.UpdatedAt
+	s.set(entry)
+	return entry, nil
+}
+
+func (s *OverlayStore) getOrCreate(ctx context.Context, guildID string, key string, initial string, createdAt time.Time) (model.KVEntry, error) {
+	entry, err := s.get(ctx, guildID, key)
+	if err == store.ErrNotFound {
+		return model.KVEntry{
+			Key:       key,
+			GuildID:   guildID,
+			Value:     initial,
+			CreatedAt: createdAt,
+		}, nil
+	}
+	return entry, err
+}
+
+func (s *OverlayStore) getList(ctx context.Context, guildID string, key string, createdAt time.Time) (model.KVEntry, []json.RawMessage, error) {
+	entry, err := s.getOrCreate(ctx, guildID, key, "[]", createdAt)
+	if err != nil {
+		return model.KVEntry{}, nil, err
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal([]byte(entry.Value), &list); err != nil || list == nil {
+		return model.KVEntry{}, nil, store.ErrWrongValueType
+	}
+
+	return entry, list, nil
+}
+
+func (s *OverlayStore) setList(entry model.KVEntry, list []json.RawMessage, updatedAt time.Time) (model.KVEntry, error) {
+	raw, err := json.Marshal(list)
+	if err != nil {
+		return model.KVEntry{}, err
+	}
+
+	entry.Value = string(raw)
+	entry.UpdatedAt = updatedAt
+	s.set(entry)
+	return entry, nil
+}
+
+// indexOfJSON compares the decoded values so that formatting differences don't matter, like jsonb equality does.
+func indexOfJSON(list []json.RawMessage, item json.RawMessage) int {
+	var want interface{}
+	if err := json.Unmarshal(item, &want); err != nil {
+		return -1
+	}
+
+	for i, raw := range list {
+		var got interface{}
+		if err := json.Unmarshal(raw, &got); err == nil && reflect.DeepEqual(got, want) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// likePatternToRegex converts an SQL LIKE pattern to an equivalent regular expression.
+func likePatternToRegex(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			b.WriteString("(?s:.*)")
+		case r == '_':
+			b.WriteString("(?s:.)")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,8 @@
+package model
+
+// Guild events that can trigger a message and actions.
+const (
+	EventTriggerMemberJoin  = "member_join"
+	EventTriggerMemberLeave = "member_leave"
+	EventTriggerMemberBoost = "member_boost"
+)
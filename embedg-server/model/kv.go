@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"gopkg.in/guregu/null.v4"
@@ -15,6 +16,11 @@ type KVEntry struct {
 	UpdatedAt time.Time
 }
 
+// IsExpired returns whether the entry has an expiry time that is not after now.
+func (e KVEntry) IsExpired(now time.Time) bool {
+	return e.ExpiresAt.Valid && !e.ExpiresAt.Time.After(now)
+}
+
 type KVEntryIncreaseParams struct {
 	Key       string
 	GuildID   string
@@ -23,3 +29,13 @@ type KVEntryIncreaseParams struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// KVEntryCollectionParams describes an atomic update of a JSON list, set or map entry.
+// Value holds the JSON encoded operand of the update.
+type KVEntryCollectionParams struct {
+	Key       string
+	GuildID   string
+	Value     json.RawMessage
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -20,9 +20,11 @@ type PlanFeatures struct {
 	IsPremium                 bool  `mapstructure:"is_premium"`
 	MaxImageUploadSize        int   `mapstructure:"max_image_upload_size"`
 	MaxScheduledMessages      int   `mapstructure:"max_scheduled_messages"`
+	MaxEventTriggers          int   `mapstructure:"max_event_triggers"`
 	PeriodicScheduledMessages bool  `mapstructure:"periodic_scheduled_messages"`
 	MaxTemplateOps            int   `mapstructure:"max_template_ops"`
 	MaxKVKeys                 int   `mapstructure:"max_kv_keys"`
+	HTTPRequests              bool  `mapstructure:"http_requests"`
 }
 
 func (f *PlanFeatures) Merge(b PlanFeatures) {
@@ -41,6 +43,9 @@ func (f *PlanFeatures) Merge(b PlanFeatures) {
 	if b.MaxScheduledMessages > f.MaxScheduledMessages {
 		f.MaxScheduledMessages = b.MaxScheduledMessages
 	}
+	if b.MaxEventTriggers > f.MaxEventTriggers {
+		f.MaxEventTriggers = b.MaxEventTriggers
+	}
 	if b.MaxTemplateOps > f.MaxTemplateOps {
 		f.MaxTemplateOps = b.MaxTemplateOps
 	}
@@ -55,6 +60,7 @@ func (f *PlanFeatures) Merge(b PlanFeatures) {
 	f.ComponentsV2 = f.ComponentsV2 || b.ComponentsV2
 	f.ComponentTypes = mergeIntSlices(f.ComponentTypes, b.ComponentTypes)
 	f.PeriodicScheduledMessages = f.PeriodicScheduledMessages || b.PeriodicScheduledMessages
+	f.HTTPRequests = f.HTTPRequests || b.HTTPRequests
 }
 
 func mergeIntSlices(a, b []int) []int {
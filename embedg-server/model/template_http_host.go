@@ -0,0 +1,9 @@
+package model
+
+import "time"
+
+type TemplateHTTPHost struct {
+	GuildID   string
+	Host      string
+	CreatedAt time.Time
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type TemplateSnippet struct {
+	GuildID   string
+	Name      string
+	Content   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type Translation struct {
+	GuildID   string
+	Locale    string
+	Key       string
+	Value     string
+	UpdatedAt time.Time
+}
@@ -119,11 +119,18 @@ func (m *ScheduledMessageManager) SendScheduledMessage(ctx context.Context, sche
 		return fmt.Errorf("could not get plan features: %w", err)
 	}
 
+	templateCtx, cancel := context.WithTimeout(ctx, template.DefaultTimeout)
+	defer cancel()
+
 	templates := template.NewContext(
-		"SCHEDULED_MESSAGE", features.MaxTemplateOps,
+		templateCtx, "SCHEDULED_MESSAGE", features.MaxTemplateOps,
 		template.NewGuildProvider(m.bot.State, scheduledMessage.GuildID, nil),
 		template.NewChannelProvider(m.bot.State, scheduledMessage.ChannelID, nil),
 		template.NewKVProvider(scheduledMessage.GuildID, m.pg, features.MaxKVKeys),
+		template.NewEntityProvider(m.bot.State, m.bot.Rest, scheduledMessage.GuildID),
+		template.NewTranslationProvider(scheduledMessage.GuildID, m.pg, template.GuildLocale(m.bot.State, scheduledMessage.GuildID)),
+		template.NewSnippetProvider(scheduledMessage.GuildID, m.pg),
+		template.NewHTTPProvider(scheduledMessage.GuildID, m.pg, nil, features.HTTPRequests),
 	)
 
 	data := &actions.MessageWithActions{}
@@ -162,7 +169,7 @@ func (m *ScheduledMessageManager) SendScheduledMessage(ctx context.Context, sche
 		return fmt.Errorf("Failed to create permission context: %w", err)
 	}
 
-	err = m.actionParser.CreateActionsForMessage(ctx, data.Actions, permContext, msg.ID, false)
+	err = m.actionParser.CreateActionsForMessage(ctx, data.Actions, permContext, scheduledMessage.GuildID, msg.ID, false)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to create actions for message")
 		return err
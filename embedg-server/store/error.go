@@ -4,3 +4,6 @@ import "errors"
 
 var ErrNotFound = errors.New("not found")
 var ErrAlreadyExists = errors.New("already exists")
+var ErrWrongValueType = errors.New("wrong value type")
+var ErrValueTooLarge = errors.New("value too large")
+var ErrTooManyKeys = errors.New("too many keys")
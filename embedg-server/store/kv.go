@@ -2,15 +2,27 @@ package store
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/merlinfuchs/embed-generator/embedg-server/model"
+	"gopkg.in/guregu/null.v4"
 )
 
 type KVEntryStore interface {
 	GetKVEntry(ctx context.Context, guildID string, key string) (model.KVEntry, error)
 	SetKVEntry(ctx context.Context, entry model.KVEntry) error
+	SetKVEntryExpiresAt(ctx context.Context, guildID string, key string, expiresAt null.Time) (model.KVEntry, error)
 	IncreaseKVEntry(ctx context.Context, params model.KVEntryIncreaseParams) (model.KVEntry, error)
 	DeleteKVEntry(ctx context.Context, guildID string, key string) (model.KVEntry, error)
 	SearchKVEntries(ctx context.Context, guildID string, pattern string) ([]model.KVEntry, error)
+	ListKVEntries(ctx context.Context, guildID string, pattern string, limit int, offset int) ([]model.KVEntry, int, error)
+	ImportKVEntries(ctx context.Context, guildID string, entries []model.KVEntry, maxKeys int) (int, error)
 	CountKVEntries(ctx context.Context, guildID string) (int, error)
+	DeleteExpiredKVEntries(ctx context.Context, before time.Time, limit int) (int, error)
+	PushKVEntryList(ctx context.Context, params model.KVEntryCollectionParams) (model.KVEntry, error)
+	AddKVEntrySetItem(ctx context.Context, params model.KVEntryCollectionParams) (model.KVEntry, bool, error)
+	RemoveKVEntryListItem(ctx context.Context, guildID string, key string, item json.RawMessage) (model.KVEntry, error)
+	KVEntryListContains(ctx context.Context, guildID string, key string, item json.RawMessage) (bool, error)
+	SetKVEntryMapField(ctx context.Context, params model.KVEntryCollectionParams) (model.KVEntry, error)
 }
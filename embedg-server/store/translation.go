@@ -0,0 +1,12 @@
+package store
+
+import (
+	"context"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/model"
+)
+
+type TranslationStore interface {
+	GetTranslationsForLocales(ctx context.Context, guildID string, locales []string) ([]model.Translation, error)
+	ReplaceTranslationsForLocale(ctx context.Context, guildID string, locale string, values map[string]string) ([]model.Translation, error)
+}
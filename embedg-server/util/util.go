@@ -56,3 +56,28 @@ func DiscordAvatarURL(id string, discriminator string, avatar string) string {
 
 	return fmt.Sprintf("https://cdn.discordapp.com/avatars/%s/%s.png", id, avatar)
 }
+
+// BoostMember returns the member that has boosted the guild if the message is a boost system message.
+// Discord doesn't send a dedicated event for boosts, so the system messages are the only reliable source.
+func BoostMember(msg *discordgo.Message) *discordgo.Member {
+	if msg.GuildID == "" || msg.Author == nil {
+		return nil
+	}
+
+	switch msg.Type {
+	case discordgo.MessageTypeUserPremiumGuildSubscription,
+		discordgo.MessageTypeUserPremiumGuildSubscriptionTierOne,
+		discordgo.MessageTypeUserPremiumGuildSubscriptionTierTwo,
+		discordgo.MessageTypeUserPremiumGuildSubscriptionTierThree:
+	default:
+		return nil
+	}
+
+	member := &discordgo.Member{GuildID: msg.GuildID}
+	if msg.Member != nil {
+		*member = *msg.Member
+		member.GuildID = msg.GuildID
+	}
+	member.User = msg.Author
+	return member
+}
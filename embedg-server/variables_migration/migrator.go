@@ -0,0 +1,287 @@
+package variables_migration
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/merlinfuchs/embed-generator/embedg-server/actions/variables"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres"
+	"github.com/merlinfuchs/embed-generator/embedg-server/db/postgres/pgmodel"
+	"github.com/rs/zerolog/log"
+)
+
+const pageSize = 500
+
+type ChangeType string
+
+const (
+	ChangeTypeSavedMessage     ChangeType = "saved_message"
+	ChangeTypeCustomCommand    ChangeType = "custom_command"
+	ChangeTypeMessageActionSet ChangeType = "message_action_set"
+)
+
+// Change describes a row that contains legacy variables.
+type Change struct {
+	Type        ChangeType
+	ID          string
+	GuildID     string
+	Name        string
+	Converted   int
+	Unsupported []string
+	Data        json.RawMessage
+}
+
+// Migrator rewrites legacy variables in stored messages and actions to templates.
+// Nothing is written to the database unless apply is set so the changes can be reviewed first.
+type Migrator struct {
+	pg *postgres.PostgresStore
+}
+
+func New(pg *postgres.PostgresStore) *Migrator {
+	return &Migrator{
+		pg: pg,
+	}
+}
+
+// ConvertGuild converts the saved messages, custom commands and action sets of sent messages of the guild.
+// Action sets that have been created before their guild was recorded are only converted by ConvertAll.
+func (m *Migrator) ConvertGuild(ctx context.Context, guildID string, apply bool) ([]Change, error) {
+	messages, err := m.pg.Q.GetSavedMessagesForGuild(ctx, sql.NullString{String: guildID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved messages: %w", err)
+	}
+
+	var changes []Change
+	for _, message := range messages {
+		change, ok, err := m.convertSavedMessage(ctx, message, apply)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			changes = append(changes, change)
+		}
+	}
+
+	commands, err := m.pg.Q.GetCustomCommands(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom commands: %w", err)
+	}
+
+	for _, command := range commands {
+		change, ok, err := m.convertCustomCommand(ctx, command, apply)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			changes = append(changes, change)
+		}
+	}
+
+	lastID := ""
+	for {
+		actionSets, err := m.pg.Q.GetMessageActionSetsForGuildPage(ctx, pgmodel.GetMessageActionSetsForGuildPageParams{
+			GuildID: sql.NullString{String: guildID, Valid: true},
+			ID:      lastID,
+			Limit:   pageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get message action sets: %w", err)
+		}
+
+		for _, actionSet := range actionSets {
+			change, ok, err := m.convertMessageActionSet(ctx, actionSet, apply)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				changes = append(changes, change)
+			}
+		}
+
+		if len(actionSets) < pageSize {
+			break
+		}
+		lastID = actionSets[len(actionSets)-1].ID
+	}
+
+	return changes, nil
+}
+
+// ConvertAll converts the saved messages, custom commands and action sets of sent messages of all guilds.
+// The callback is called for every row that contains legacy variables.
+func (m *Migrator) ConvertAll(ctx context.Context, apply bool, callback func(Change)) error {
+	lastID := ""
+	for {
+		messages, err := m.pg.Q.GetSavedMessagesPage(ctx, pgmodel.GetSavedMessagesPageParams{
+			ID:    lastID,
+			Limit: pageSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get saved messages: %w", err)
+		}
+
+		for _, message := range messages {
+			change, ok, err := m.convertSavedMessage(ctx, message, apply)
+			if err != nil {
+				return err
+			}
+			if ok {
+				callback(change)
+			}
+		}
+
+		if len(messages) < pageSize {
+			break
+		}
+		lastID = messages[len(messages)-1].ID
+	}
+
+	lastID = ""
+	for {
+		commands, err := m.pg.Q.GetCustomCommandsPage(ctx, pgmodel.GetCustomCommandsPageParams{
+			ID:    lastID,
+			Limit: pageSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get custom commands: %w", err)
+		}
+
+		for _, command := range commands {
+			change, ok, err := m.convertCustomCommand(ctx, command, apply)
+			if err != nil {
+				return err
+			}
+			if ok {
+				callback(change)
+			}
+		}
+
+		if len(commands) < pageSize {
+			break
+		}
+		lastID = commands[len(commands)-1].ID
+	}
+
+	lastID = ""
+	for {
+		actionSets, err := m.pg.Q.GetMessageActionSetsPage(ctx, pgmodel.GetMessageActionSetsPageParams{
+			ID:    lastID,
+			Limit: pageSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get message action sets: %w", err)
+		}
+
+		for _, actionSet := range actionSets {
+			change, ok, err := m.convertMessageActionSet(ctx, actionSet, apply)
+			if err != nil {
+				return err
+			}
+			if ok {
+				callback(change)
+			}
+		}
+
+		if len(actionSets) < pageSize {
+			break
+		}
+		lastID = actionSets[len(actionSets)-1].ID
+	}
+
+	return nil
+}
+
+func (m *Migrator) convertSavedMessage(ctx context.Context, message pgmodel.SavedMessage, apply bool) (Change, bool, error) {
+	data, conversion, err := variables.ConvertMessageJSON(message.Data)
+	if err != nil {
+		log.Warn().Err(err).Str("saved_message_id", message.ID).Msg("Skipping saved message with invalid data")
+		return Change{}, false, nil
+	}
+	if !conversion.Changed() && len(conversion.Unsupported) == 0 {
+		return Change{}, false, nil
+	}
+
+	if apply && conversion.Changed() {
+		err = m.pg.Q.UpdateSavedMessageData(ctx, pgmodel.UpdateSavedMessageDataParams{
+			ID:   message.ID,
+			Data: data,
+		})
+		if err != nil {
+			return Change{}, false, fmt.Errorf("failed to update saved message: %w", err)
+		}
+	}
+
+	return Change{
+		Type:        ChangeTypeSavedMessage,
+		ID:          message.ID,
+		GuildID:     message.GuildID.String,
+		Name:        message.Name,
+		Converted:   conversion.Converted,
+		Unsupported: conversion.Unsupported,
+		Data:        data,
+	}, true, nil
+}
+
+func (m *Migrator) convertCustomCommand(ctx context.Context, command pgmodel.CustomCommand, apply bool) (Change, bool, error) {
+	data, conversion, err := variables.ConvertActionSetJSON(command.Actions)
+	if err != nil {
+		log.Warn().Err(err).Str("custom_command_id", command.ID).Msg("Skipping custom command with invalid actions")
+		return Change{}, false, nil
+	}
+	if !conversion.Changed() && len(conversion.Unsupported) == 0 {
+		return Change{}, false, nil
+	}
+
+	if apply && conversion.Changed() {
+		err = m.pg.Q.UpdateCustomCommandActions(ctx, pgmodel.UpdateCustomCommandActionsParams{
+			ID:      command.ID,
+			Actions: data,
+		})
+		if err != nil {
+			return Change{}, false, fmt.Errorf("failed to update custom command: %w", err)
+		}
+	}
+
+	return Change{
+		Type:        ChangeTypeCustomCommand,
+		ID:          command.ID,
+		GuildID:     command.GuildID,
+		Name:        command.Name,
+		Converted:   conversion.Converted,
+		Unsupported: conversion.Unsupported,
+		Data:        data,
+	}, true, nil
+}
+
+func (m *Migrator) convertMessageActionSet(ctx context.Context, actionSet pgmodel.MessageActionSet, apply bool) (Change, bool, error) {
+	data, conversion, err := variables.ConvertActionSetJSON(actionSet.Actions)
+	if err != nil {
+		log.Warn().Err(err).Str("message_action_set_id", actionSet.ID).Msg("Skipping message action set with invalid actions")
+		return Change{}, false, nil
+	}
+	if !conversion.Changed() && len(conversion.Unsupported) == 0 {
+		return Change{}, false, nil
+	}
+
+	if apply && conversion.Changed() {
+		err = m.pg.Q.UpdateMessageActionSetActions(ctx, pgmodel.UpdateMessageActionSetActionsParams{
+			ID:      actionSet.ID,
+			Actions: data,
+		})
+		if err != nil {
+			return Change{}, false, fmt.Errorf("failed to update message action set: %w", err)
+		}
+	}
+
+	return Change{
+		Type:        ChangeTypeMessageActionSet,
+		ID:          actionSet.ID,
+		GuildID:     actionSet.GuildID.String,
+		Name:        actionSet.MessageID + "/" + actionSet.SetID,
+		Converted:   conversion.Converted,
+		Unsupported: conversion.Unsupported,
+		Data:        data,
+	}, true, nil
+}